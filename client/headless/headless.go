@@ -0,0 +1,212 @@
+package clheadless
+
+import (
+	"ATowerDefense/game"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type (
+	build struct {
+		round int
+		tower string
+		// Placed by the bot when not set.
+		x, y   int
+		placed bool
+	}
+
+	clHeadless struct {
+		gm  *game.Game
+		pid int
+
+		builds    []*build
+		maxRounds int
+	}
+)
+
+// Simulate a game without renderer as fast as possible and print the results.
+//
+// Towers are placed by following the build order file, a line per tower formatted as `<round> <tower> [x y]`.
+// Towers without a position and all coins left over after the build order are spent by a bot.
+func Run(gc game.GameConfig, buildOrder string, maxRounds int) error {
+	if gc.Towers == nil {
+		gc.Towers = game.Towers
+	}
+
+	builds := []*build{}
+	if buildOrder != "" {
+		f, err := os.Open(buildOrder)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		builds, err = parseBuildOrder(f, gc.Towers)
+		if err != nil {
+			return err
+		}
+	}
+
+	gm := game.NewGame(gc)
+	if err := gm.Start(); err != nil {
+		return err
+	}
+	pid := gm.AddPlayer()
+
+	cl := &clHeadless{
+		gm: gm, pid: pid,
+		builds: builds, maxRounds: maxRounds,
+	}
+	cl.run()
+	cl.print(os.Stdout)
+	return nil
+}
+
+func parseBuildOrder(r io.Reader, towers []game.TowerType) ([]*build, error) {
+	builds := []*build{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 && len(fields) != 4 {
+			return nil, errors.New("build order line " + strconv.Itoa(line) + ": expected `<round> <tower> [x y]`")
+		}
+
+		b := &build{tower: fields[1], x: -1, y: -1}
+		if !slices.ContainsFunc(towers, func(obj game.TowerType) bool { return obj.Name == b.tower }) {
+			return nil, errors.New("build order line " + strconv.Itoa(line) + ": " + game.Errors.TowerNotExists.Error())
+		}
+		var err error
+		if b.round, err = strconv.Atoi(fields[0]); err != nil {
+			return nil, errors.New("build order line " + strconv.Itoa(line) + ": " + err.Error())
+		}
+		if len(fields) == 4 {
+			if b.x, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, errors.New("build order line " + strconv.Itoa(line) + ": " + err.Error())
+			}
+			if b.y, err = strconv.Atoi(fields[3]); err != nil {
+				return nil, errors.New("build order line " + strconv.Itoa(line) + ": " + err.Error())
+			}
+		}
+		builds = append(builds, b)
+	}
+	return builds, scanner.Err()
+}
+
+func (cl *clHeadless) run() {
+	for {
+		phase, round := "", 0
+		cl.gm.View(func() { phase, round = cl.gm.GS.Phase, cl.gm.GS.Round })
+		if phase == "lost" || (phase == "building" && round >= cl.maxRounds) {
+			return
+		}
+
+		if phase == "building" {
+			cl.build(round + 1)
+			if err := cl.gm.StartRound(); err != nil {
+				return
+			}
+		}
+		cl.gm.Step(1)
+	}
+}
+
+func (cl *clHeadless) build(round int) {
+	for _, b := range cl.builds {
+		if b.placed || b.round > round {
+			continue
+		}
+		x, y := b.x, b.y
+		if x < 0 || y < 0 {
+			x, y = cl.bestPlacement(b.tower)
+		}
+		if err := cl.gm.PlaceTower(b.tower, x, y, cl.pid); err != nil {
+			if err == game.Errors.InsufficientFunds {
+				return
+			}
+			continue
+		}
+		b.placed = true
+	}
+
+	// Spend what is left on the most expensive affordable tower.
+	for {
+		coins, towers := 0, []game.TowerType{}
+		cl.gm.View(func() { coins, towers = cl.gm.GetCoins(cl.pid), cl.gm.GC.Towers })
+		i := -1
+		for j, tower := range towers {
+			if tower.Cost <= coins && (i < 0 || tower.Cost > towers[i].Cost) {
+				i = j
+			}
+		}
+		if i < 0 {
+			return
+		}
+		x, y := cl.bestPlacement(towers[i].Name)
+		if x < 0 || cl.gm.PlaceTower(towers[i].Name, x, y, cl.pid) != nil {
+			return
+		}
+	}
+}
+
+// Free tile covering the most road tiles with the towers range, -1 if none.
+func (cl *clHeadless) bestPlacement(name string) (int, int) {
+	bestX, bestY, best := -1, -1, 0
+	cl.gm.View(func() {
+		i := slices.IndexFunc(cl.gm.GC.Towers, func(obj game.TowerType) bool { return obj.Name == name })
+		if i < 0 {
+			return
+		}
+		r := cl.gm.GC.Towers[i].Range
+
+		for y := range cl.gm.GC.FieldHeight {
+			for x := range cl.gm.GC.FieldWidth {
+				if cl.gm.CheckCollisions(x, y) {
+					continue
+				}
+				coverage := 0
+				for _, road := range cl.gm.GS.Roads {
+					roadX, roadY := road.Cord()
+					if max(roadX-x, x-roadX) <= r && max(roadY-y, y-roadY) <= r {
+						coverage += 1
+					}
+				}
+				if coverage > best {
+					bestX, bestY, best = x, y, coverage
+				}
+			}
+		}
+	})
+	return bestX, bestY
+}
+
+func (cl *clHeadless) print(w io.Writer) {
+	cl.gm.View(func() {
+		stats := cl.gm.GS.Stats
+		fmt.Fprintf(w, "Seed:         %v\n", cl.gm.GC.Seed)
+		fmt.Fprintf(w, "Round:        %v (%v)\n", cl.gm.GS.Round, cl.gm.GS.Phase)
+		fmt.Fprintf(w, "Health:       %v\n", cl.gm.GetHealth(cl.pid))
+		fmt.Fprintf(w, "Coins earned: %v\n", stats.CoinsEarned)
+		fmt.Fprintf(w, "Towers:       %v\n", len(cl.gm.GS.Towers))
+
+		fmt.Fprintln(w, "Damage:")
+		for _, name := range slices.Sorted(maps.Keys(stats.Damage)) {
+			fmt.Fprintf(w, "  %-10v %v\n", name, stats.Damage[name])
+		}
+
+		fmt.Fprintln(w, "Leaks:")
+		for i, leaks := range stats.Leaks {
+			if leaks > 0 {
+				fmt.Fprintf(w, "  round %-4v %v\n", i+1, leaks)
+			}
+		}
+	})
+}
@@ -0,0 +1,514 @@
+package clsdl
+
+import (
+	"ATowerDefense/game"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type clEditor struct {
+	*clSDL
+
+	m    *game.Map
+	file string
+
+	// Selected tool, see `editorTools`.
+	tool int
+	// Road index painted last, continued by the next painted tile, -1 when not painting.
+	last int
+	// Result of validating the map after the last edit, empty when valid.
+	validation string
+}
+
+const editorObstacleCost = 100
+
+// Tools used by left click and return, right click and delete erase.
+var editorTools = []string{"road", "obstacle", "spawn", "exit"}
+
+// Edit the map in file, a new map of width by height tiles when file does not exist.
+func Edit(file string, width, height int, assets embed.FS) error {
+	m := &game.Map{
+		Name:  strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)),
+		Width: width, Height: height,
+		Roads: []game.MapRoad{}, Obstacles: []game.MapObstacle{},
+	}
+	if f, err := os.Open(file); err == nil {
+		defer func() { _ = f.Close() }()
+		// Not validated, maps being worked on are allowed to be invalid.
+		if err := json.NewDecoder(f).Decode(m); err != nil {
+			return errors.New("map: " + err.Error())
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	cl, err := newWindow(m.Width, m.Height, assets)
+	if err != nil {
+		return err
+	}
+	defer cl.destroy()
+
+	ed := &clEditor{clSDL: cl, m: m, file: file, last: -1}
+	ed.validate()
+	for {
+		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			if err := ed.input(event); err == game.Errors.Exit {
+				return nil
+			} else if err != nil {
+				ed.warningMsg = err.Error()
+				ed.warningMsgTimeout = time.Now().Add(time.Second * 3)
+			}
+		}
+		if err := ed.draw(); err != nil {
+			return err
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+}
+
+func (ed *clEditor) input(event sdl.Event) error {
+	switch event := event.(type) {
+	case *sdl.QuitEvent:
+		return game.Errors.Exit
+
+	case *sdl.KeyboardEvent:
+		if event.State != sdl.PRESSED {
+			return nil
+		}
+
+		switch event.Keysym.Scancode {
+		case sdl.SCANCODE_ESCAPE:
+			return game.Errors.Exit
+		case sdl.SCANCODE_RETURN, sdl.SCANCODE_KP_ENTER:
+			ed.paint(ed.selectedX, ed.selectedY)
+		case sdl.SCANCODE_BACKSPACE, sdl.SCANCODE_DELETE:
+			ed.erase(ed.selectedX, ed.selectedY)
+		case sdl.SCANCODE_TAB:
+			ed.tool, ed.last = (ed.tool+1)%len(editorTools), -1
+		case sdl.SCANCODE_1, sdl.SCANCODE_2, sdl.SCANCODE_3, sdl.SCANCODE_4:
+			tool := map[sdl.Scancode]int{sdl.SCANCODE_1: 0, sdl.SCANCODE_2: 1, sdl.SCANCODE_3: 2, sdl.SCANCODE_4: 3}[event.Keysym.Scancode]
+			ed.tool, ed.last = tool, -1
+		case sdl.SCANCODE_O:
+			if err := ed.save(); err != nil {
+				return err
+			}
+			ed.warningMsg = "Map saved"
+			ed.warningMsgTimeout = time.Now().Add(time.Second * 3)
+		case sdl.SCANCODE_T:
+			switch ed.theme {
+			case "old":
+				ed.themeNew = "city"
+			case "city":
+				ed.themeNew = "old"
+			}
+
+		case sdl.SCANCODE_MINUS, sdl.SCANCODE_KP_MINUS:
+			ed.resize(ed.m.Width-1, ed.m.Height)
+		case sdl.SCANCODE_EQUALS, sdl.SCANCODE_KP_PLUS:
+			ed.resize(ed.m.Width+1, ed.m.Height)
+		case sdl.SCANCODE_LEFTBRACKET:
+			ed.resize(ed.m.Width, ed.m.Height-1)
+		case sdl.SCANCODE_RIGHTBRACKET:
+			ed.resize(ed.m.Width, ed.m.Height+1)
+
+		case sdl.SCANCODE_W, sdl.SCANCODE_K:
+			ed.selectedY = max(ed.selectedY-1, max(0, -ed.viewOffsetY))
+		case sdl.SCANCODE_S, sdl.SCANCODE_J:
+			ed.selectedY = min(ed.selectedY+1, (ed.m.Height+min(0, -ed.viewOffsetY))-1)
+		case sdl.SCANCODE_D, sdl.SCANCODE_L:
+			ed.selectedX = min(ed.selectedX+1, (ed.m.Width+min(0, -ed.viewOffsetX))-1)
+		case sdl.SCANCODE_A, sdl.SCANCODE_H:
+			ed.selectedX = max(ed.selectedX-1, max(0, -ed.viewOffsetX))
+
+		case sdl.SCANCODE_UP:
+			ed.viewOffsetY = min(ed.viewOffsetY+1, (ed.m.Height-min(int(ed.windowH/tileSize), ed.m.Height))+6)
+		case sdl.SCANCODE_DOWN:
+			ed.viewOffsetY = max(ed.viewOffsetY-1, -5)
+		case sdl.SCANCODE_RIGHT:
+			ed.viewOffsetX = max(ed.viewOffsetX-1, -5)
+		case sdl.SCANCODE_LEFT:
+			ed.viewOffsetX = min(ed.viewOffsetX+1, (ed.m.Width-min(int(ed.windowW/tileSize), ed.m.Width))+5)
+		}
+
+		return nil
+
+	case *sdl.MouseMotionEvent:
+		if event.State&sdl.ButtonMMask() != 0 {
+			if time.Since(ed.lastMiddleMouseMotion) < time.Millisecond*50 {
+				return nil
+			}
+			ed.lastMiddleMouseMotion = time.Now()
+
+			if event.XRel > 0 {
+				ed.viewOffsetX = min(ed.viewOffsetX+1, (ed.m.Width-min(int(ed.windowW/tileSize), ed.m.Width))+5)
+			} else if event.XRel < 0 {
+				ed.viewOffsetX = max(ed.viewOffsetX-1, -5)
+			}
+			if event.YRel > 0 {
+				ed.viewOffsetY = min(ed.viewOffsetY+1, (ed.m.Height-min(int(ed.windowH/tileSize), ed.m.Height))+6)
+			} else if event.YRel < 0 {
+				ed.viewOffsetY = max(ed.viewOffsetY-1, -5)
+			}
+			return nil
+		}
+
+		x, y := ed.tileAt(event.X, event.Y)
+		if x == ed.selectedX && y == ed.selectedY {
+			return nil
+		}
+		ed.selectedX, ed.selectedY = x, y
+		// Dragging paints or erases every tile passed.
+		if event.State&sdl.ButtonLMask() != 0 {
+			ed.paint(x, y)
+		} else if event.State&sdl.ButtonRMask() != 0 {
+			ed.erase(x, y)
+		}
+
+		return nil
+
+	case *sdl.MouseButtonEvent:
+		if event.State != sdl.PRESSED {
+			return nil
+		}
+
+		ed.selectedX, ed.selectedY = ed.tileAt(event.X, event.Y)
+		switch event.Button {
+		case sdl.BUTTON_LEFT:
+			ed.last = -1
+			ed.paint(ed.selectedX, ed.selectedY)
+		case sdl.BUTTON_RIGHT:
+			ed.erase(ed.selectedX, ed.selectedY)
+		}
+
+		return nil
+
+	case *sdl.MouseWheelEvent:
+		if event.Y > 0 {
+			ed.tool, ed.last = max(ed.tool-1, 0), -1
+		} else if event.Y < 0 {
+			ed.tool, ed.last = min(ed.tool+1, len(editorTools)-1), -1
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// Tile under the window position x, y.
+func (ed *clEditor) tileAt(x, y int32) (int, int) {
+	return min(max(int(x/tileSize)-ed.viewOffsetX, 0), ed.m.Width-1), min(max(int(y/tileSize)-ed.viewOffsetY, 0), ed.m.Height-1)
+}
+
+func (ed *clEditor) save() error {
+	f, err := os.Create(ed.file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return ed.m.Save(f)
+}
+
+func (ed *clEditor) validate() {
+	ed.validation = ""
+	if err := ed.m.Validate(); err != nil {
+		ed.validation = err.Error()
+	}
+}
+
+// Direction from road i to the neighbouring tile x, y, empty when not next to it.
+func (ed *clEditor) direction(i, x, y int) string {
+	for _, dir := range []string{"up", "right", "down", "left"} {
+		if nextX, nextY := game.Neighbour(ed.m.Roads[i].X, ed.m.Roads[i].Y, dir, ed.m.Width, ed.m.Height); nextX == x && nextY == y {
+			return dir
+		}
+	}
+	return ""
+}
+
+func (ed *clEditor) roadAt(x, y int) int {
+	return slices.IndexFunc(ed.m.Roads, func(obj game.MapRoad) bool { return obj.X == x && obj.Y == y })
+}
+
+// Roads enemies continue on from road i, the road its exit points at when `MapRoad.Next` is not set.
+func (ed *clEditor) nexts(i int) []int {
+	road := ed.m.Roads[i]
+	if len(road.Next) > 0 || road.Exit == "end" {
+		return road.Next
+	}
+	x, y := game.Neighbour(road.X, road.Y, road.Exit, ed.m.Width, ed.m.Height)
+	if next := ed.roadAt(x, y); next >= 0 {
+		return []int{next}
+	}
+	return nil
+}
+
+// Whether another road continues on road i.
+func (ed *clEditor) entered(i int) bool {
+	for j := range ed.m.Roads {
+		if j != i && slices.Contains(ed.nexts(j), i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ed *clEditor) paint(x, y int) {
+	defer ed.validate()
+
+	switch editorTools[ed.tool] {
+	case "road":
+		ed.paintRoad(x, y)
+
+	case "obstacle":
+		if i := slices.IndexFunc(ed.m.Obstacles, func(obj game.MapObstacle) bool { return obj.X == x && obj.Y == y }); i >= 0 {
+			ed.m.Obstacles = slices.Delete(ed.m.Obstacles, i, i+1)
+		} else if ed.roadAt(x, y) < 0 {
+			ed.m.Obstacles = append(ed.m.Obstacles, game.MapObstacle{X: x, Y: y, Cost: editorObstacleCost})
+		}
+
+	case "spawn":
+		i := ed.roadAt(x, y)
+		if i < 0 {
+			ed.addRoad(x, y, "start")
+			return
+		}
+		for j := range ed.m.Roads {
+			road := &ed.m.Roads[j]
+			if !slices.Contains(ed.nexts(j), i) {
+				continue
+			}
+			if len(road.Next) > 1 {
+				road.Next = slices.DeleteFunc(road.Next, func(next int) bool { return next == i })
+			} else {
+				road.Exit, road.Next = "end", nil
+			}
+		}
+		ed.m.Roads[i].Entrance = "start"
+
+	case "exit":
+		i := ed.roadAt(x, y)
+		if i < 0 {
+			// Roads painted with the road tool already end in an exit.
+			return
+		}
+		nexts := ed.nexts(i)
+		ed.m.Roads[i].Exit, ed.m.Roads[i].Next = "end", nil
+		for _, next := range nexts {
+			if !ed.entered(next) {
+				ed.m.Roads[next].Entrance = "start"
+			}
+		}
+	}
+}
+
+// Extend the road painted last to x, y, starts a new road when x, y is not next to it.
+func (ed *clEditor) paintRoad(x, y int) {
+	i := ed.roadAt(x, y)
+	dir := ""
+	if ed.last >= 0 && ed.last != i {
+		dir = ed.direction(ed.last, x, y)
+	}
+	if dir == "" || dir == ed.m.Roads[ed.last].Entrance {
+		// Strokes started on a road continue from it.
+		if i < 0 {
+			i = ed.addRoad(x, y, "start")
+		}
+		ed.last = i
+		return
+	}
+
+	if i < 0 {
+		i = ed.addRoad(x, y, game.Opposite(dir))
+	} else if ed.m.Roads[i].Entrance == "start" {
+		ed.m.Roads[i].Entrance = game.Opposite(dir)
+	}
+
+	road := &ed.m.Roads[ed.last]
+	if road.Exit == "end" {
+		road.Exit = dir
+	} else if road.Exit != dir && !slices.Contains(ed.nexts(ed.last), i) {
+		// Fork off a road that already continues elsewhere.
+		road.Next = append(ed.nexts(ed.last), i)
+	}
+	ed.last = i
+}
+
+func (ed *clEditor) addRoad(x, y int, entrance string) int {
+	ed.m.Obstacles = slices.DeleteFunc(ed.m.Obstacles, func(obj game.MapObstacle) bool { return obj.X == x && obj.Y == y })
+	ed.m.Roads = append(ed.m.Roads, game.MapRoad{X: x, Y: y, Entrance: entrance, Exit: "end"})
+	return len(ed.m.Roads) - 1
+}
+
+func (ed *clEditor) erase(x, y int) {
+	defer ed.validate()
+
+	ed.m.Obstacles = slices.DeleteFunc(ed.m.Obstacles, func(obj game.MapObstacle) bool { return obj.X == x && obj.Y == y })
+	for i := ed.roadAt(x, y); i >= 0; i = ed.roadAt(x, y) {
+		ed.removeRoad(i)
+	}
+	ed.last = -1
+}
+
+// Remove road i, roads leading into it become exits and roads only entered from it become spawns.
+func (ed *clEditor) removeRoad(i int) {
+	nexts := ed.nexts(i)
+	ed.m.Roads[i].Exit, ed.m.Roads[i].Next = "end", nil
+	for _, next := range nexts {
+		if !ed.entered(next) {
+			ed.m.Roads[next].Entrance = "start"
+		}
+	}
+	for j := range ed.m.Roads {
+		road := &ed.m.Roads[j]
+		if !slices.Contains(ed.nexts(j), i) {
+			continue
+		}
+		if len(road.Next) > 1 {
+			road.Next = slices.DeleteFunc(road.Next, func(next int) bool { return next == i })
+		} else {
+			road.Exit, road.Next = "end", nil
+		}
+	}
+
+	ed.m.Roads = slices.Delete(ed.m.Roads, i, i+1)
+	for j := range ed.m.Roads {
+		for k, next := range ed.m.Roads[j].Next {
+			if next > i {
+				ed.m.Roads[j].Next[k]--
+			}
+		}
+	}
+}
+
+func (ed *clEditor) resize(width, height int) {
+	defer ed.validate()
+
+	ed.m.Width, ed.m.Height = max(width, 1), max(height, 1)
+	for i := slices.IndexFunc(ed.m.Roads, ed.outOfBounds); i >= 0; i = slices.IndexFunc(ed.m.Roads, ed.outOfBounds) {
+		ed.removeRoad(i)
+	}
+	ed.m.Obstacles = slices.DeleteFunc(ed.m.Obstacles, func(obj game.MapObstacle) bool { return obj.X >= ed.m.Width || obj.Y >= ed.m.Height })
+	ed.selectedX, ed.selectedY = min(ed.selectedX, ed.m.Width-1), min(ed.selectedY, ed.m.Height-1)
+	ed.last = -1
+
+	ed.windowW, ed.windowH = tileSize*int32(ed.m.Width), tileSize*int32(ed.m.Height)
+	ed.window.SetSize(ed.windowW, ed.windowH)
+	ed.viewOffsetX, ed.viewOffsetY = 0, 0
+}
+
+func (ed *clEditor) outOfBounds(obj game.MapRoad) bool {
+	return obj.X >= ed.m.Width || obj.Y >= ed.m.Height
+}
+
+// Tile of the road or obstacle the validation error points at.
+func (ed *clEditor) invalidTile() (int, int, bool) {
+	for _, list := range []string{"roads", "obstacles"} {
+		i, n := strings.Index(ed.validation, list+"["), 0
+		if i < 0 {
+			continue
+		}
+		if _, err := fmt.Sscanf(ed.validation[i:], list+"[%d]", &n); err != nil {
+			continue
+		}
+		if list == "roads" && n < len(ed.m.Roads) {
+			return ed.m.Roads[n].X, ed.m.Roads[n].Y, true
+		} else if list == "obstacles" && n < len(ed.m.Obstacles) {
+			return ed.m.Obstacles[n].X, ed.m.Obstacles[n].Y, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (ed *clEditor) draw() error {
+	if ed.theme != ed.themeNew {
+		if err := ed.loadTheme(ed.themeNew); err != nil {
+			return err
+		}
+	}
+
+	if err := ed.renderer.SetDrawColor(87, 87, 87, 255); err != nil {
+		return err
+	}
+	if err := ed.renderer.Clear(); err != nil {
+		return err
+	}
+	if err := ed.drawBackground(ed.m.Width, ed.m.Height); err != nil {
+		return err
+	}
+
+	for _, road := range ed.m.Roads {
+		dst := ed.newRect(int32(road.X+ed.viewOffsetX), int32(road.Y+ed.viewOffsetY))
+		src, ok := textureRoads[road.Entrance+";"+road.Exit]
+		if !ok {
+			// Single tile roads are both spawn and exit.
+			src = textureRoads["start;up"]
+		}
+		if err := ed.renderer.Copy(ed.textures.roads, &src, &dst); err != nil {
+			return err
+		}
+	}
+
+	for _, obstacle := range ed.m.Obstacles {
+		dst := ed.newRect(int32(obstacle.X+ed.viewOffsetX), int32(obstacle.Y+ed.viewOffsetY))
+		src := textureObstacles[(obstacle.X+obstacle.Y)%len(textureObstacles)]
+		if err := ed.renderer.Copy(ed.textures.environment, &src, &dst); err != nil {
+			return err
+		}
+	}
+
+	if x, y, ok := ed.invalidTile(); ok {
+		if err := ed.renderer.SetDrawColor(255, 0, 0, 85); err != nil {
+			return err
+		}
+		dst := ed.newRect(int32(x+ed.viewOffsetX), int32(y+ed.viewOffsetY))
+		if err := ed.renderer.FillRect(&dst); err != nil {
+			return err
+		}
+	}
+
+	dst := ed.newRect(int32(ed.selectedX+ed.viewOffsetX), int32(ed.selectedY+ed.viewOffsetY))
+	src := textureUI["crosshair"]
+	if err := ed.renderer.Copy(ed.textures.ui, &src, &dst); err != nil {
+		return err
+	}
+
+	if err := ed.renderString(ed.m.Name+" "+strconv.Itoa(ed.m.Width)+"x"+strconv.Itoa(ed.m.Height), 0, 0); err != nil {
+		return err
+	}
+	validation := "valid"
+	if ed.validation != "" {
+		validation = ed.validation
+	}
+	if err := ed.renderString(validation, 0, tileSize); err != nil {
+		return err
+	}
+
+	for i, tool := range editorTools {
+		if i == ed.tool {
+			tool += " <"
+		}
+		if err := ed.renderString(tool, 0, (ed.windowH-(tileSize*int32(len(editorTools))))+(tileSize*int32(i))); err != nil {
+			return err
+		}
+	}
+
+	if time.Until(ed.warningMsgTimeout) > 0 {
+		if err := ed.renderString(ed.warningMsg, (ed.windowW/2)-(tileSize/2)-((tileSize/2)*int32(len(ed.warningMsg)/2)), (ed.windowH)-(tileSize)); err != nil {
+			return err
+		}
+	}
+
+	ed.renderer.Present()
+	return nil
+}
@@ -0,0 +1,287 @@
+package clsdl
+
+import (
+	"ATowerDefense/game"
+	"ATowerDefense/server"
+	"strconv"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type clLobby struct {
+	*clSDL
+	client *server.Client
+
+	// Chat message being typed.
+	text  string
+	ready bool
+}
+
+type clSelect struct {
+	*clSDL
+	games []server.LobbyInfo
+
+	// Line picked, 0 creates a game and every other line joins a listed game.
+	selected int
+	// Code being typed, joined instead of the picked line when set.
+	text string
+	// Code of the game picked, -1 until picked.
+	code int
+}
+
+// Code of the game to join picked from the games on the server, 0 to create a game.
+func (cl *clSDL) selectGame(client *server.Client) (int, error) {
+	games, err := client.List()
+	if err != nil {
+		return 0, err
+	}
+
+	sdl.StartTextInput()
+	defer sdl.StopTextInput()
+
+	sel := &clSelect{clSDL: cl, games: games, code: -1}
+	for sel.code < 0 {
+		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			if err := sel.input(event); err == game.Errors.Exit {
+				return 0, err
+			} else if err != nil {
+				sel.warningMsg = err.Error()
+				sel.warningMsgTimeout = time.Now().Add(time.Second * 3)
+			}
+		}
+		if err := sel.draw(); err != nil {
+			return 0, err
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+	return sel.code, nil
+}
+
+func (sel *clSelect) input(event sdl.Event) error {
+	switch event := event.(type) {
+	case *sdl.QuitEvent:
+		return game.Errors.Exit
+
+	case *sdl.TextInputEvent:
+		for _, char := range event.GetText() {
+			if char >= '0' && char <= '9' && len(sel.text) < 4 {
+				sel.text += string(char)
+			}
+		}
+
+	case *sdl.KeyboardEvent:
+		if event.State != sdl.PRESSED {
+			return nil
+		}
+
+		switch event.Keysym.Scancode {
+		case sdl.SCANCODE_ESCAPE:
+			return game.Errors.Exit
+		case sdl.SCANCODE_UP:
+			sel.selected = max(sel.selected-1, 0)
+		case sdl.SCANCODE_DOWN:
+			sel.selected = min(sel.selected+1, len(sel.games))
+		case sdl.SCANCODE_RETURN, sdl.SCANCODE_KP_ENTER:
+			if sel.text == "" && sel.selected == 0 {
+				sel.code = 0
+			} else if sel.text == "" {
+				sel.code = sel.games[sel.selected-1].Code
+			} else if code, err := strconv.Atoi(sel.text); err != nil || code < 1000 {
+				return server.Errors.GameNotExists
+			} else {
+				sel.code = code
+			}
+		case sdl.SCANCODE_BACKSPACE:
+			if len(sel.text) > 0 {
+				sel.text = sel.text[:len(sel.text)-1]
+			}
+		}
+	}
+
+	return nil
+}
+
+func (sel *clSelect) draw() error {
+	if err := sel.renderer.SetDrawColor(87, 87, 87, 255); err != nil {
+		return err
+	}
+	if err := sel.renderer.Clear(); err != nil {
+		return err
+	}
+	if err := sel.drawBackground(int(sel.windowW/tileSize), int(sel.windowH/tileSize)); err != nil {
+		return err
+	}
+
+	lines := []string{"Create"}
+	for _, info := range sel.games {
+		line := "Join " + strconv.Itoa(info.Code) + " " + strconv.Itoa(len(info.Players)) + " players " + info.Config.String()
+		if info.Started {
+			line += " started"
+		}
+		lines = append(lines, line)
+	}
+	for i, line := range lines {
+		if i == sel.selected {
+			line = "> " + line
+		} else {
+			line = "  " + line
+		}
+		if err := sel.renderString(line, 0, tileSize*int32(i)); err != nil {
+			return err
+		}
+	}
+
+	if err := sel.renderString("Code "+sel.text, 0, sel.windowH-(tileSize*2)); err != nil {
+		return err
+	}
+	if err := sel.renderString("up down pick, type a code, return join, esc leave", 0, sel.windowH-tileSize); err != nil {
+		return err
+	}
+
+	if time.Until(sel.warningMsgTimeout) > 0 {
+		if err := sel.renderString(sel.warningMsg, (sel.windowW/2)-(tileSize/2)-((tileSize/2)*int32(len(sel.warningMsg)/2)), sel.windowH-(tileSize*3)); err != nil {
+			return err
+		}
+	}
+
+	sel.renderer.Present()
+	return nil
+}
+
+// Wait in the lobby of the joined game until it starts.
+func (cl *clSDL) lobby(client *server.Client) error {
+	sdl.StartTextInput()
+	defer sdl.StopTextInput()
+
+	lb := &clLobby{clSDL: cl, client: client}
+	for {
+		select {
+		case <-client.Started():
+			return nil
+		case <-client.Done():
+			if err := client.Err(); err != nil {
+				return err
+			}
+			return server.Errors.Closed
+		default:
+		}
+
+		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+			if err := lb.input(event); err == game.Errors.Exit {
+				return err
+			} else if err != nil {
+				lb.warningMsg = err.Error()
+				lb.warningMsgTimeout = time.Now().Add(time.Second * 3)
+			}
+		}
+		if err := lb.draw(); err != nil {
+			return err
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+}
+
+func (lb *clLobby) input(event sdl.Event) error {
+	switch event := event.(type) {
+	case *sdl.QuitEvent:
+		return game.Errors.Exit
+
+	case *sdl.TextInputEvent:
+		lb.text += event.GetText()
+
+	case *sdl.KeyboardEvent:
+		if event.State != sdl.PRESSED {
+			return nil
+		}
+
+		switch event.Keysym.Scancode {
+		case sdl.SCANCODE_ESCAPE:
+			return game.Errors.Exit
+		case sdl.SCANCODE_TAB:
+			if err := lb.client.SetReady(!lb.ready); err != nil {
+				return err
+			}
+			lb.ready = !lb.ready
+		case sdl.SCANCODE_RETURN, sdl.SCANCODE_KP_ENTER:
+			if lb.text == "" {
+				return lb.client.Start()
+			}
+			text := lb.text
+			lb.text = ""
+			return lb.client.Chat(text)
+		case sdl.SCANCODE_BACKSPACE:
+			if runes := []rune(lb.text); len(runes) > 0 {
+				lb.text = string(runes[:len(runes)-1])
+			}
+		}
+	}
+
+	return nil
+}
+
+func (lb *clLobby) draw() error {
+	if err := lb.renderer.SetDrawColor(87, 87, 87, 255); err != nil {
+		return err
+	}
+	if err := lb.renderer.Clear(); err != nil {
+		return err
+	}
+	info, chat := lb.client.Lobby()
+	if err := lb.drawBackground(info.Config.FieldWidth, info.Config.FieldHeight); err != nil {
+		return err
+	}
+
+	lines := []string{"Game " + strconv.Itoa(info.Code), info.Config.String(), ""}
+	for _, player := range info.Players {
+		line := "  " + player.Name
+		if player.PID == lb.client.PID {
+			line = "> " + player.Name
+		}
+		if player.PID == info.Host {
+			line += " host"
+		} else if player.Ready {
+			line += " ready"
+		} else {
+			line += " not ready"
+		}
+		lines = append(lines, line)
+	}
+	if info.Spectators > 0 {
+		lines = append(lines, "  "+strconv.Itoa(info.Spectators)+" spectating")
+	}
+	lines = append(lines, "")
+
+	// The prompt and hint take the last 2 rows.
+	rows := max(int(lb.windowH/tileSize)-len(lines)-2, 0)
+	for _, msg := range chat[max(len(chat)-rows, 0):] {
+		lines = append(lines, msg.Name+": "+msg.Text)
+	}
+	for i, line := range lines {
+		if err := lb.renderString(line, 0, tileSize*int32(i)); err != nil {
+			return err
+		}
+	}
+
+	hint := "tab ready, return chat, esc leave"
+	if info.Host == lb.client.PID {
+		hint = "return chat or start when empty, esc leave"
+	} else if lb.client.PID < 0 {
+		hint = "spectating, esc leave"
+	}
+	if err := lb.renderString("> "+lb.text, 0, lb.windowH-(tileSize*2)); err != nil {
+		return err
+	}
+	if err := lb.renderString(hint, 0, lb.windowH-tileSize); err != nil {
+		return err
+	}
+
+	if time.Until(lb.warningMsgTimeout) > 0 {
+		if err := lb.renderString(lb.warningMsg, (lb.windowW/2)-(tileSize/2)-((tileSize/2)*int32(len(lb.warningMsg)/2)), lb.windowH-(tileSize*3)); err != nil {
+			return err
+		}
+	}
+
+	lb.renderer.Present()
+	return nil
+}
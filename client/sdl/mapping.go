@@ -52,81 +52,27 @@ var (
 		"left;end":  {X: 3 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
 	}
 
-	textureTowers = map[string][16]sdl.Rect{
-		"Soldier": {
-			{X: 0 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 1 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 2 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 3 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 4 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 5 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 6 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 7 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 8 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 9 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 10 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 11 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 12 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 13 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 14 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 15 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-		},
-		"Sniper": {
-			{X: 0 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 1 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 2 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 3 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 4 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 5 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 6 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 7 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 8 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 9 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 10 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 11 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 12 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 13 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 14 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 15 * tileSize, Y: 1 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-		},
-		"Scout": {
-			{X: 0 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 1 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 2 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 3 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 4 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 5 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 6 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 7 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 8 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 9 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 10 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 11 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 12 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 13 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 14 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 15 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-		},
-		"Heavy": {
-			{X: 0 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 1 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 2 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 3 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 4 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 5 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 6 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 7 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 8 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 9 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 10 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 11 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 12 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 13 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 14 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-			{X: 15 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
-		},
+	// Offset Y by `TowerType.Sprite` rows.
+	textureTowers = [16]sdl.Rect{
+		{X: 0 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 1 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 2 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 3 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 4 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 5 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 6 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 7 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 8 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 9 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 10 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 11 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 12 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 13 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 14 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+		{X: 15 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
 	}
 
+	// Offset Y by `EnemyType.Sprite` rows.
 	textureEnemies = map[string]sdl.Rect{
 		"up;down":    {X: 0 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
 		"right;left": {X: 2 * tileSize, Y: 0 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
@@ -281,5 +227,7 @@ var (
 		"barblue;7": {X: 7 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
 		"barblue;8": {X: 8 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
 		"barblue;9": {X: 9 * tileSize, Y: 2 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
+
+		"projectile": {X: 0 * tileSize, Y: 3 * tileSize, W: 1 * tileSize, H: 1 * tileSize},
 	}
 )
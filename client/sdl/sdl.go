@@ -2,10 +2,13 @@ package clsdl
 
 import (
 	"ATowerDefense/game"
+	"ATowerDefense/server"
 	"embed"
 	"fmt"
 	"math"
 	"math/rand/v2"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -25,8 +28,11 @@ type (
 	}
 
 	clSDL struct {
-		gm  *game.Game
-		pid int
+		gm     *game.Game
+		pid    int
+		replay bool
+		// Connected to a hosted game, see `server.Connect`.
+		remote bool
 
 		window   *sdl.Window
 		renderer *sdl.Renderer
@@ -37,6 +43,12 @@ type (
 		selectedX, selectedY,
 		viewOffsetX, viewOffsetY,
 		selectedTower int
+		// Player receiving gifts and sent enemies.
+		giftTo int
+		// Watching a hosted game, only moving the crosshair, panning and local settings are allowed.
+		spectator bool
+		// Enemy sent in versus, see `game.SendTypes`.
+		selectedSend int
 
 		theme    string
 		themeNew string
@@ -48,16 +60,49 @@ type (
 	}
 )
 
+const (
+	saveFile   = "ATowerDefense.save"
+	replayFile = "ATowerDefense.replay"
+	mapFile    = "ATowerDefense.map"
+	// Ticks skipped per seek in replays.
+	seekTicks = 250
+	// Coins per gift.
+	giftCoins = 10
+)
+
 var (
 	backgroundCache = map[int]map[int]sdl.Rect{}
 	obstacleCache   = map[int]sdl.Rect{}
 
 	// 0.0 - 0.5; lower makes the rotate anamation longer
 	rotateAnimationOffset = float64(1) / 3
+
+	// Enemy color mods by status effect, see `game.EffectKinds`.
+	effectTints = map[string]sdl.Color{
+		"":       {R: 255, G: 255, B: 255},
+		"stun":   {R: 255, G: 255, B: 96},
+		"burn":   {R: 255, G: 128, B: 64},
+		"poison": {R: 128, G: 255, B: 96},
+		"slow":   {R: 128, G: 192, B: 255},
+		"shred":  {R: 160, G: 160, B: 160},
+	}
+
+	// Keys that do not issue commands, the only keys handled for spectators.
+	spectatorKeys = []sdl.Scancode{
+		sdl.SCANCODE_ESCAPE, sdl.SCANCODE_T, sdl.SCANCODE_M,
+		sdl.SCANCODE_W, sdl.SCANCODE_K, sdl.SCANCODE_S, sdl.SCANCODE_J, sdl.SCANCODE_D, sdl.SCANCODE_L, sdl.SCANCODE_A, sdl.SCANCODE_H,
+		sdl.SCANCODE_UP, sdl.SCANCODE_DOWN, sdl.SCANCODE_RIGHT, sdl.SCANCODE_LEFT,
+	}
 )
 
 func Run(gc game.GameConfig, assets embed.FS) error {
-	cl, err := newSDL(gc, assets)
+	gm := game.NewGame(gc)
+	if err := gm.Start(); err != nil {
+		return err
+	}
+	pid := gm.AddPlayer()
+
+	cl, err := newSDL(gm, pid, assets)
 	if err != nil {
 		return err
 	}
@@ -66,19 +111,91 @@ func Run(gc game.GameConfig, assets embed.FS) error {
 	return err
 }
 
-func newSDL(gc game.GameConfig, assets embed.FS) (*clSDL, error) {
-	gm := game.NewGame(gc)
-	if err := gm.Start(); err != nil {
+// Join the game with code on the server on addr, creates a game with lc when code is 0.
+//
+// Spectators watch the game with code without playing.
+func Connect(addr string, code int, lc server.LobbyConfig, spectate bool, assets embed.FS) error {
+	client, err := server.Connect(addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	cl, err := newWindow(lc.FieldWidth, lc.FieldHeight, assets)
+	if err != nil {
+		return err
+	}
+	if code == 0 {
+		if code, err = cl.selectGame(client); err != nil {
+			cl.destroy()
+			if err == game.Errors.Exit {
+				return nil
+			}
+			return err
+		}
+	}
+	if spectate {
+		err = client.Spectate(code)
+	} else if code == 0 {
+		err = client.Create(lc)
+	} else {
+		err = client.Join(code)
+	}
+	if err != nil {
+		cl.destroy()
+		return err
+	}
+
+	info, _ := client.Lobby()
+	cl.windowW, cl.windowH = tileSize*int32(info.Config.FieldWidth), tileSize*int32(info.Config.FieldHeight)
+	cl.window.SetSize(cl.windowW, cl.windowH)
+	if err := cl.lobby(client); err != nil {
+		cl.destroy()
+		if err == game.Errors.Exit {
+			return nil
+		}
+		return err
+	}
+
+	cl.gm, cl.pid, cl.remote, cl.spectator = client.Game, client.PID, true, spectate
+	// Maps of the host set their own field size.
+	gc := cl.config()
+	cl.windowW, cl.windowH = tileSize*int32(gc.FieldWidth), tileSize*int32(gc.FieldHeight)
+	cl.window.SetSize(cl.windowW, cl.windowH)
+	cl.selectedX, cl.selectedY = gc.FieldWidth/2, gc.FieldHeight/2
+	defer cl.stop()
+	cl.start()
+	return client.Err()
+}
+
+func Replay(gm *game.Game, assets embed.FS) error {
+	cl, err := newSDL(gm, 0, assets)
+	if err != nil {
+		return err
+	}
+	cl.replay = true
+	defer cl.stop()
+	cl.start()
+	return err
+}
+
+func newSDL(gm *game.Game, pid int, assets embed.FS) (*clSDL, error) {
+	cl, err := newWindow(gm.GC.FieldWidth, gm.GC.FieldHeight, assets)
+	if err != nil {
 		return nil, err
 	}
-	pid := gm.AddPlayer()
+	cl.gm, cl.pid = gm, pid
+	return cl, nil
+}
 
+// Window sized to fit a field of width by height tiles.
+func newWindow(width, height int, assets embed.FS) (*clSDL, error) {
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		return nil, err
 	}
 
 	tileSize, theme := int32(64), "city"
-	w, err := sdl.CreateWindow("ATowerDefense", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, tileSize*int32(gm.GC.FieldWidth), tileSize*int32(gm.GC.FieldHeight), sdl.WINDOW_OPENGL)
+	w, err := sdl.CreateWindow("ATowerDefense", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, tileSize*int32(width), tileSize*int32(height), sdl.WINDOW_OPENGL)
 	if err != nil {
 		return nil, err
 	}
@@ -92,12 +209,10 @@ func newSDL(gc game.GameConfig, assets embed.FS) (*clSDL, error) {
 	}
 
 	cl := &clSDL{
-		gm: gm, pid: pid,
-
 		window: w, renderer: r, assets: assets,
-		windowW: tileSize * int32(gm.GC.FieldWidth), windowH: tileSize * int32(gm.GC.FieldHeight),
+		windowW: tileSize * int32(width), windowH: tileSize * int32(height),
 
-		selectedX: gm.GC.FieldWidth / 2, selectedY: gm.GC.FieldHeight / 2,
+		selectedX: width / 2, selectedY: height / 2,
 		viewOffsetX: 0, viewOffsetY: 0,
 		selectedTower: 0,
 
@@ -115,7 +230,7 @@ func newSDL(gc game.GameConfig, assets embed.FS) (*clSDL, error) {
 func (cl *clSDL) start() {
 	go func() {
 		defer cl.stop()
-		for cl.gm.GS.State != "stopped" {
+		for !cl.gm.Stopped() {
 			if err := cl.input(); err != nil {
 				if err == game.Errors.Exit {
 					break
@@ -130,10 +245,19 @@ func (cl *clSDL) start() {
 }
 
 func (cl *clSDL) stop() {
-	if cl.gm.GS.State != "stopped" {
-		_ = cl.gm.Stop()
+	// Only the first caller succeeds, the renderer is never used after Stop returns.
+	if err := cl.gm.Stop(); err != nil {
+		return
+	}
+	if !cl.replay && !cl.remote {
+		if err := cl.saveReplay(); err != nil {
+			fmt.Println(err)
+		}
 	}
+	cl.destroy()
+}
 
+func (cl *clSDL) destroy() {
 	if cl.window != nil {
 		_ = cl.window.Destroy()
 		cl.window = nil
@@ -183,10 +307,11 @@ func (cl *clSDL) draw(processTime time.Duration) error {
 		return err
 	}
 
-	if err := cl.drawField(); err != nil {
+	view := cl.gm.ReadOnly()
+	if err := cl.drawField(view); err != nil {
 		return err
 	}
-	if err := cl.drawUI(processTime); err != nil {
+	if err := cl.drawUI(view, processTime); err != nil {
 		return err
 	}
 
@@ -202,6 +327,7 @@ func (cl *clSDL) draw(processTime time.Duration) error {
 
 func (cl *clSDL) input() error {
 	event := sdl.WaitEventTimeout(100)
+	gc := cl.config()
 	switch event := event.(type) {
 	case *sdl.QuitEvent:
 		return game.Errors.Exit
@@ -210,6 +336,9 @@ func (cl *clSDL) input() error {
 		if event.State != sdl.PRESSED {
 			return nil
 		}
+		if cl.spectator && !slices.Contains(spectatorKeys, event.Keysym.Scancode) {
+			return nil
+		}
 
 		switch event.Keysym.Scancode {
 		case sdl.SCANCODE_ESCAPE:
@@ -223,10 +352,11 @@ func (cl *clSDL) input() error {
 			}
 
 		case sdl.SCANCODE_RETURN, sdl.SCANCODE_KP_ENTER:
-			if len(game.Towers) < cl.selectedTower {
+			towers := cl.towers()
+			if cl.selectedTower >= len(towers) {
 				return nil
 			}
-			if err := cl.gm.PlaceTower(game.Towers[cl.selectedTower].Name, cl.selectedX, cl.selectedY, cl.pid); err != nil {
+			if err := cl.gm.PlaceTower(towers[cl.selectedTower].Name, cl.selectedX, cl.selectedY, cl.pid); err != nil {
 				if err != game.Errors.InvalidPlacement {
 					cl.warningMsg = err.Error()
 					cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
@@ -240,6 +370,58 @@ func (cl *clSDL) input() error {
 					}
 				}
 			}
+		case sdl.SCANCODE_Z, sdl.SCANCODE_X, sdl.SCANCODE_C:
+			path := map[sdl.Scancode]int{sdl.SCANCODE_Z: 0, sdl.SCANCODE_X: 1, sdl.SCANCODE_C: 2}[event.Keysym.Scancode]
+			if err := cl.gm.UpgradeTower(cl.selectedX, cl.selectedY, cl.pid, path); err != nil {
+				cl.warningMsg = err.Error()
+				cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
+			}
+		case sdl.SCANCODE_TAB:
+			if err := cl.cycleTargeting(); err != nil {
+				cl.warningMsg = err.Error()
+				cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
+			}
+		case sdl.SCANCODE_G:
+			if cl.giftTo == cl.pid {
+				cl.giftTo = cl.nextPlayer(cl.giftTo)
+			}
+			if err := cl.gm.GiftCoins(cl.pid, cl.giftTo, giftCoins); err != nil {
+				cl.warningMsg = err.Error()
+				cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
+			}
+		case sdl.SCANCODE_N:
+			cl.giftTo = cl.nextPlayer(cl.giftTo)
+		case sdl.SCANCODE_V:
+			if cl.giftTo == cl.pid {
+				cl.giftTo = cl.nextPlayer(cl.giftTo)
+			}
+			if err := cl.gm.SendEnemy(game.SendTypes[cl.selectedSend].Enemy, cl.pid, cl.giftTo); err != nil {
+				cl.warningMsg = err.Error()
+				cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
+			}
+		case sdl.SCANCODE_B:
+			cl.selectedSend = (cl.selectedSend + 1) % len(game.SendTypes)
+		case sdl.SCANCODE_O:
+			if err := cl.save(); err != nil {
+				cl.warningMsg = err.Error()
+			} else {
+				cl.warningMsg = "Game saved"
+			}
+			cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
+		case sdl.SCANCODE_I:
+			if err := cl.load(); err != nil {
+				cl.warningMsg = err.Error()
+			} else {
+				cl.warningMsg = "Game loaded"
+			}
+			cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
+		case sdl.SCANCODE_M:
+			if err := cl.exportMap(); err != nil {
+				cl.warningMsg = err.Error()
+			} else {
+				cl.warningMsg = "Map exported"
+			}
+			cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
 		case sdl.SCANCODE_T:
 			switch cl.theme {
 			case "old":
@@ -251,36 +433,42 @@ func (cl *clSDL) input() error {
 		case sdl.SCANCODE_W, sdl.SCANCODE_K:
 			cl.selectedY = max(cl.selectedY-1, max(0, -cl.viewOffsetY))
 		case sdl.SCANCODE_S, sdl.SCANCODE_J:
-			cl.selectedY = min(cl.selectedY+1, (cl.gm.GC.FieldHeight+min(0, -cl.viewOffsetY))-1)
+			cl.selectedY = min(cl.selectedY+1, (gc.FieldHeight+min(0, -cl.viewOffsetY))-1)
 		case sdl.SCANCODE_D, sdl.SCANCODE_L:
-			cl.selectedX = min(cl.selectedX+1, (cl.gm.GC.FieldWidth+min(0, -cl.viewOffsetX))-1)
+			cl.selectedX = min(cl.selectedX+1, (gc.FieldWidth+min(0, -cl.viewOffsetX))-1)
 		case sdl.SCANCODE_A, sdl.SCANCODE_H:
 			cl.selectedX = max(cl.selectedX-1, max(0, -cl.viewOffsetX))
 
 		case sdl.SCANCODE_UP:
-			cl.viewOffsetY = min(cl.viewOffsetY+1, (cl.gm.GC.FieldHeight-min(int(cl.windowH/tileSize), cl.gm.GC.FieldHeight))+6)
+			cl.viewOffsetY = min(cl.viewOffsetY+1, (gc.FieldHeight-min(int(cl.windowH/tileSize), gc.FieldHeight))+6)
 			cl.selectedY = max(cl.selectedY-1, max(0, -cl.viewOffsetY))
 		case sdl.SCANCODE_DOWN:
 			cl.viewOffsetY = max(cl.viewOffsetY-1, -5)
-			cl.selectedY = min(cl.selectedY+1, (cl.gm.GC.FieldHeight+min(0, -cl.viewOffsetY))-1)
+			cl.selectedY = min(cl.selectedY+1, (gc.FieldHeight+min(0, -cl.viewOffsetY))-1)
 		case sdl.SCANCODE_RIGHT:
 			cl.viewOffsetX = max(cl.viewOffsetX-1, -5)
-			cl.selectedX = min(cl.selectedX+1, (cl.gm.GC.FieldWidth+min(0, -cl.viewOffsetX))-1)
+			cl.selectedX = min(cl.selectedX+1, (gc.FieldWidth+min(0, -cl.viewOffsetX))-1)
 		case sdl.SCANCODE_LEFT:
-			cl.viewOffsetX = min(cl.viewOffsetX+1, (cl.gm.GC.FieldWidth-min(int(cl.windowW/tileSize), cl.gm.GC.FieldWidth))+5)
+			cl.viewOffsetX = min(cl.viewOffsetX+1, (gc.FieldWidth-min(int(cl.windowW/tileSize), gc.FieldWidth))+5)
 			cl.selectedX = max(cl.selectedX-1, max(0, -cl.viewOffsetX))
 
 		case sdl.SCANCODE_LEFTBRACKET:
+			if cl.replay {
+				cl.seek(-seekTicks)
+				return nil
+			}
 			cl.selectedTower = max(cl.selectedTower-1, 0)
 		case sdl.SCANCODE_RIGHTBRACKET:
-			cl.selectedTower = min(cl.selectedTower+1, len(game.Towers)-1)
+			if cl.replay {
+				cl.seek(seekTicks)
+				return nil
+			}
+			cl.selectedTower = min(cl.selectedTower+1, len(cl.towers())-1)
 
 		case sdl.SCANCODE_EQUALS, sdl.SCANCODE_KP_PLUS:
-			if cl.gm.GC.TickDelay/(1<<cl.gm.GC.GameSpeed) >= time.Millisecond {
-				cl.gm.GC.GameSpeed = min(cl.gm.GC.GameSpeed+1, 9)
-			}
+			cl.gm.SetGameSpeed(gc.GameSpeed + 1)
 		case sdl.SCANCODE_MINUS, sdl.SCANCODE_KP_MINUS:
-			cl.gm.GC.GameSpeed = max(cl.gm.GC.GameSpeed-1, 0)
+			cl.gm.SetGameSpeed(gc.GameSpeed - 1)
 		}
 
 		return nil
@@ -294,35 +482,36 @@ func (cl *clSDL) input() error {
 			cl.lastMiddleMouseMotion = time.Now()
 
 			if event.XRel > 0 {
-				cl.viewOffsetX = min(cl.viewOffsetX+1, (cl.gm.GC.FieldWidth-min(int(cl.windowW/tileSize), cl.gm.GC.FieldWidth))+5)
+				cl.viewOffsetX = min(cl.viewOffsetX+1, (gc.FieldWidth-min(int(cl.windowW/tileSize), gc.FieldWidth))+5)
 			} else if event.XRel < 0 {
 				cl.viewOffsetX = max(cl.viewOffsetX-1, -5)
 			}
 
 			if event.YRel > 0 {
-				cl.viewOffsetY = min(cl.viewOffsetY+1, (cl.gm.GC.FieldHeight-min(int(cl.windowH/tileSize), cl.gm.GC.FieldHeight))+6)
+				cl.viewOffsetY = min(cl.viewOffsetY+1, (gc.FieldHeight-min(int(cl.windowH/tileSize), gc.FieldHeight))+6)
 			} else if event.YRel < 0 {
 				cl.viewOffsetY = max(cl.viewOffsetY-1, -5)
 			}
 
 		default:
-			cl.selectedX = min(max(int(event.X/tileSize)-cl.viewOffsetX, 0), (cl.gm.GC.FieldWidth+min(0, -cl.viewOffsetX))-1)
-			cl.selectedY = min(max(int(event.Y/tileSize)-cl.viewOffsetY, 0), (cl.gm.GC.FieldHeight+min(0, -cl.viewOffsetY))-1)
+			cl.selectedX = min(max(int(event.X/tileSize)-cl.viewOffsetX, 0), (gc.FieldWidth+min(0, -cl.viewOffsetX))-1)
+			cl.selectedY = min(max(int(event.Y/tileSize)-cl.viewOffsetY, 0), (gc.FieldHeight+min(0, -cl.viewOffsetY))-1)
 		}
 
 		return nil
 
 	case *sdl.MouseButtonEvent:
-		if event.State != sdl.RELEASED {
+		if event.State != sdl.RELEASED || cl.spectator {
 			return nil
 		}
 
 		switch event.Button {
 		case sdl.BUTTON_LEFT:
-			if len(game.Towers) < cl.selectedTower {
+			towers := cl.towers()
+			if cl.selectedTower >= len(towers) {
 				return nil
 			}
-			if err := cl.gm.PlaceTower(game.Towers[cl.selectedTower].Name, cl.selectedX, cl.selectedY, cl.pid); err != nil {
+			if err := cl.gm.PlaceTower(towers[cl.selectedTower].Name, cl.selectedX, cl.selectedY, cl.pid); err != nil {
 				cl.warningMsg = err.Error()
 				cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
 			}
@@ -338,7 +527,9 @@ func (cl *clSDL) input() error {
 			}
 
 		case sdl.BUTTON_X1, sdl.BUTTON_X2:
-			if cl.gm.GS.Phase == "defending" {
+			phase := ""
+			cl.gm.View(func() { phase = cl.gm.GS.Phase })
+			if phase == "defending" {
 				cl.gm.TogglePause()
 			} else {
 				if err := cl.gm.StartRound(); err != nil {
@@ -354,7 +545,7 @@ func (cl *clSDL) input() error {
 		if event.Y > 0 {
 			cl.selectedTower = max(cl.selectedTower-1, 0)
 		} else if event.Y < 0 {
-			cl.selectedTower = min(cl.selectedTower+1, len(game.Towers)-1)
+			cl.selectedTower = min(cl.selectedTower+1, len(cl.towers())-1)
 		}
 		return nil
 	}
@@ -362,6 +553,95 @@ func (cl *clSDL) input() error {
 	return nil
 }
 
+func (cl *clSDL) save() error {
+	f, err := os.Create(saveFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return cl.gm.Save(f)
+}
+
+func (cl *clSDL) load() error {
+	f, err := os.Open(saveFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	if err := cl.gm.Restore(f); err != nil {
+		return err
+	}
+	// Saves may be made with different tower types.
+	cl.selectedTower = min(cl.selectedTower, len(cl.towers())-1)
+	return nil
+}
+
+func (cl *clSDL) exportMap() error {
+	f, err := os.Create(mapFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return cl.gm.ExportMap(f)
+}
+
+func (cl *clSDL) saveReplay() error {
+	f, err := os.Create(replayFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return cl.gm.SaveReplay(f)
+}
+
+func (cl *clSDL) seek(ticks int) {
+	tick := 0
+	cl.gm.View(func() { tick = cl.gm.GS.Tick })
+	if err := cl.gm.Seek(max(0, tick+ticks)); err != nil {
+		cl.warningMsg = err.Error()
+		cl.warningMsgTimeout = time.Now().Add(time.Second * 3)
+	}
+}
+
+// Switch the tower under the crosshair to the next targeting mode.
+func (cl *clSDL) cycleTargeting() error {
+	mode := ""
+	cl.gm.View(func() {
+		if towers := cl.gm.GetCollisionTowers(cl.selectedX, cl.selectedY); len(towers) == 1 {
+			mode = towers[0].Targeting
+		}
+	})
+	i := slices.Index(game.TargetingModes, mode)
+	return cl.gm.SetTargeting(cl.selectedX, cl.selectedY, cl.pid, game.TargetingModes[(i+1)%len(game.TargetingModes)])
+}
+
+// Player after pid besides the own player, the own player when playing alone.
+func (cl *clSDL) nextPlayer(pid int) int {
+	players := 0
+	cl.gm.View(func() { players = len(cl.gm.Players) })
+	if players < 2 {
+		return cl.pid
+	}
+	if pid = (pid + 1) % players; pid == cl.pid {
+		pid = (pid + 1) % players
+	}
+	return pid
+}
+
+// Settings of the game, safe to call outside of draw.
+func (cl *clSDL) config() game.GameConfig {
+	gc := game.GameConfig{}
+	cl.gm.View(func() { gc = cl.gm.GC })
+	return gc
+}
+
+// Tower types that can be placed, safe to call outside of draw.
+func (cl *clSDL) towers() []game.TowerType {
+	towers := []game.TowerType{}
+	cl.gm.View(func() { towers = cl.gm.GC.Towers })
+	return towers
+}
+
 func (cl *clSDL) loadTheme(theme string) error {
 	loadTexture := func(file string) (*sdl.Texture, error) {
 		var rw *sdl.RWops
@@ -481,9 +761,9 @@ func (cl *clSDL) renderString(str string, x, y int32) error {
 	return nil
 }
 
-func (cl *clSDL) drawField() error {
-	for y := range cl.gm.GC.FieldHeight {
-		for x := range cl.gm.GC.FieldWidth {
+func (cl *clSDL) drawBackground(width, height int) error {
+	for y := range height {
+		for x := range width {
 			dst := cl.newRect(int32(x+cl.viewOffsetX), int32(y+cl.viewOffsetY))
 			src, ok := backgroundCache[x][y]
 			if !ok {
@@ -498,8 +778,17 @@ func (cl *clSDL) drawField() error {
 			}
 		}
 	}
+	return nil
+}
+
+func (cl *clSDL) drawField(view game.Viewer) error {
+	gc, gs := view.GetConfig(), view.GetState()
+
+	if err := cl.drawBackground(gc.FieldWidth, gc.FieldHeight); err != nil {
+		return err
+	}
 
-	for _, road := range cl.gm.GS.Roads {
+	for _, road := range gs.Roads {
 		x, y := road.Cord()
 		dst := cl.newRect(int32(x+cl.viewOffsetX), int32(y+cl.viewOffsetY))
 		src := textureRoads[road.DirEntrance+";"+road.DirExit]
@@ -508,7 +797,7 @@ func (cl *clSDL) drawField() error {
 		}
 	}
 
-	for _, obstacle := range cl.gm.GS.Obstacles {
+	for _, obstacle := range gs.Obstacles {
 		x, y := obstacle.Cord()
 		dst := cl.newRect(int32(x+cl.viewOffsetX), int32(y+cl.viewOffsetY))
 		src, ok := obstacleCache[obstacle.UID]
@@ -521,13 +810,28 @@ func (cl *clSDL) drawField() error {
 		}
 	}
 
-	for _, tower := range cl.gm.GS.Towers {
+	for _, tower := range gs.Towers {
 		x, y := tower.Cord()
 		dst := cl.newRect(int32(x+cl.viewOffsetX), int32(y+cl.viewOffsetY))
-		src := textureTowers[tower.Name][min(int32((tower.Rotation/360)*16), 15)]
+		src := textureTowers[min(int32((tower.Rotation/360)*16), 15)]
+		src.Y += int32(tower.Sprite) * tileSize
+		alpha := uint8(255)
+		if tower.Disabled > 0 {
+			alpha = 96
+		}
+		if err := cl.textures.towers.SetAlphaMod(alpha); err != nil {
+			return err
+		}
 		if err := cl.renderer.Copy(cl.textures.towers, &src, &dst); err != nil {
 			return err
 		}
+		if tower.Tier > 0 {
+			src := textureText[rune('0'+min(tower.Tier, 9))]
+			badge := sdl.Rect{X: dst.X + (tileSize / 2), Y: dst.Y + (tileSize / 2), W: tileSize / 2, H: tileSize / 2}
+			if err := cl.renderer.Copy(cl.textures.text, &src, &badge); err != nil {
+				return err
+			}
+		}
 		dst.Y -= int32(float64(tileSize) * 0.75)
 		src = textureUI["barblue;"+strconv.Itoa(int(math.Round(min(tower.ReloadProgress, 1)*9)))]
 
@@ -536,20 +840,22 @@ func (cl *clSDL) drawField() error {
 		}
 	}
 
-	for _, enemy := range cl.gm.GS.Enemies {
-		if enemy.Progress == 0.0 {
+	tickProgress := view.TickProgress()
+	for _, enemy := range gs.Enemies {
+		progress := enemy.ProgressAt(tickProgress)
+		if progress == 0.0 || len(enemy.Route) == 0 {
 			continue
 		}
 
-		x, y := enemy.Cord()
+		road := gs.Roads[enemy.Route[min(int(progress), len(enemy.Route)-1)]]
+		x, y := road.Cord()
 		dst := cl.newRect(int32(x+cl.viewOffsetX), int32(y+cl.viewOffsetY))
-		road := cl.gm.GS.Roads[min(int(enemy.Progress), len(cl.gm.GS.Roads)-1)]
 		src := textureEnemies[road.DirEntrance+";"+road.DirExit]
 
-		progdec := (enemy.Progress - float64(int(enemy.Progress)))
-		if enemy.Progress < 1 {
+		progdec := (progress - float64(int(progress)))
+		if progress < 1 {
 			progdec = (progdec * rotateAnimationOffset) + (1 - rotateAnimationOffset)
-		} else if int(enemy.Progress) >= len(cl.gm.GS.Roads)-1 {
+		} else if int(progress) >= len(enemy.Route)-1 {
 			progdec = (progdec * rotateAnimationOffset)
 		}
 
@@ -603,6 +909,11 @@ func (cl *clSDL) drawField() error {
 			}
 		}
 
+		src.Y += int32(enemy.EnemyType().Sprite) * tileSize
+		tint := effectTints[enemy.Effect()]
+		if err := cl.textures.enemies.SetColorMod(tint.R, tint.G, tint.B); err != nil {
+			return err
+		}
 		if err := cl.renderer.Copy(cl.textures.enemies, &src, &dst); err != nil {
 			return err
 		}
@@ -613,15 +924,30 @@ func (cl *clSDL) drawField() error {
 		}
 	}
 
+	for _, projectile := range gs.Projectiles {
+		x, y := projectile.PositionAt(tickProgress)
+		dst := sdl.Rect{
+			X: int32((x + float64(cl.viewOffsetX)) * float64(tileSize)),
+			Y: int32((y + float64(cl.viewOffsetY)) * float64(tileSize)),
+			W: tileSize, H: tileSize,
+		}
+		src := textureUI["projectile"]
+		if err := cl.renderer.Copy(cl.textures.ui, &src, &dst); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (cl *clSDL) drawUI(processTime time.Duration) error {
-	if cl.gm.GS.Phase == "building" {
+func (cl *clSDL) drawUI(view game.Viewer, processTime time.Duration) error {
+	gc, gs, players := view.GetConfig(), view.GetState(), view.GetPlayers()
+
+	if gs.Phase == "building" && !cl.spectator {
 		if err := cl.renderer.SetDrawColor(255, 0, 0, 85); err != nil {
 			return err
 		}
-		r := game.Towers[cl.selectedTower].Range
+		r := gc.Towers[min(cl.selectedTower, len(gc.Towers)-1)].Range
 		dst := cl.newRect(int32(cl.selectedX+cl.viewOffsetX-r), int32(cl.selectedY+cl.viewOffsetY-r))
 		dst.W, dst.H = int32((r*2)+1)*tileSize, int32((r*2)+1)*tileSize
 		if err := cl.renderer.FillRect(&dst); err != nil {
@@ -635,44 +961,109 @@ func (cl *clSDL) drawUI(processTime time.Duration) error {
 		return err
 	}
 
-	phase := cl.gm.GS.Phase + " R:" + strconv.Itoa(cl.gm.GS.Round)
-	if cl.gm.GS.Phase == "defending" {
-		phase += " E:" + strconv.Itoa(len(cl.gm.GS.Enemies))
+	phase := gs.Phase + " R:" + strconv.Itoa(gs.Round)
+	if gs.Phase == "defending" {
+		phase += " E:" + strconv.Itoa(len(gs.Enemies))
+	}
+	if gc.Mode == "versus" && !cl.spectator {
+		sendType := game.SendTypes[cl.selectedSend]
+		phase += " V:" + sendType.Enemy + " " + strconv.Itoa(sendType.Cost)
+	}
+	if cl.replay {
+		phase += " " + (time.Duration(gs.Tick) * game.TickDuration).Truncate(time.Second).String()
+	}
+	if cl.spectator {
+		phase += " spectating"
 	}
 
 	if err := cl.renderString(phase, 0, 0); err != nil {
 		return err
 	}
 
-	// if processTime >= cl.gm.GC.TickDelay/time.Duration(1<<max(0, cl.gm.GC.GameSpeed-1)) {}
-	stats := fmt.Sprintf("%v %v %v %v", cl.gm.GC.GameSpeed, processTime.Milliseconds(), cl.gm.Players[cl.pid].Coins, cl.gm.GS.Health)
+	// if processTime >= gc.TickDelay {}
+	stats := fmt.Sprintf("%v %v %v %v", gc.GameSpeed, processTime.Milliseconds(), view.GetCoins(cl.pid), view.GetHealth(cl.pid))
 	stats = strings.Repeat(" ", int(cl.windowW/32)-len(stats)-1) + stats
 
 	if err := cl.renderString(stats, 0, 0); err != nil {
 		return err
 	}
 
-	for i, tower := range game.Towers {
+	// Stats per player with several players or spectating below the boss bar, the player receiving gifts and sent enemies is marked with g.
+	for i, player := range players {
+		if len(players) < 2 && !cl.spectator {
+			break
+		}
+		msg := fmt.Sprintf("P%v %v %vk %vd", i+1, view.GetCoins(i), player.Stats.Kills, player.Stats.Damage)
+		if gc.Mode == "versus" && player.Phase == "lost" {
+			msg = fmt.Sprintf("P%v lost", i+1)
+		} else if gc.Mode == "versus" {
+			msg = fmt.Sprintf("P%v %v %vhp +%v", i+1, view.GetCoins(i), player.Health, player.Income)
+		}
+		if i == cl.pid {
+			msg = "> " + msg
+		} else if i == cl.giftTo {
+			msg = "g " + msg
+		}
+		if err := cl.renderString(msg, cl.windowW-((tileSize/2)*int32(len(msg)+1)), tileSize+(tileSize/4)+(tileSize*int32(i))); err != nil {
+			return err
+		}
+	}
+
+	if boss := view.Boss(); boss != nil {
+		if err := cl.renderer.SetDrawColor(0, 0, 0, 170); err != nil {
+			return err
+		}
+		bar := sdl.Rect{X: tileSize / 2, Y: tileSize, W: cl.windowW - tileSize, H: tileSize / 4}
+		if err := cl.renderer.FillRect(&bar); err != nil {
+			return err
+		}
+		if err := cl.renderer.SetDrawColor(200, 0, 0, 255); err != nil {
+			return err
+		}
+		bar.W = int32(float64(bar.W) * float64(boss.Health) / float64(boss.StartHealth))
+		if err := cl.renderer.FillRect(&bar); err != nil {
+			return err
+		}
+	}
+
+	for i, tower := range gc.Towers {
 		if i == cl.selectedTower {
-			if err := cl.renderString(tower.Name+" <", 0, (cl.windowH-(tileSize*int32(len(game.Towers))))+(tileSize*int32(i))); err != nil {
+			if err := cl.renderString(tower.Name+" <", 0, (cl.windowH-(tileSize*int32(len(gc.Towers))))+(tileSize*int32(i))); err != nil {
 				return err
 			}
 			continue
 		}
-		if err := cl.renderString(tower.Name, 0, (cl.windowH-(tileSize*int32(len(game.Towers))))+(tileSize*int32(i))); err != nil {
+		if err := cl.renderString(tower.Name, 0, (cl.windowH-(tileSize*int32(len(gc.Towers))))+(tileSize*int32(i))); err != nil {
 			return err
 		}
 	}
 
-	if cl.gm.GS.State == "paused" {
+	if towers := view.GetCollisionTowers(cl.selectedX, cl.selectedY); len(towers) == 1 && towers[0].Owner == cl.pid {
+		upgrades := []string{"tab " + towers[0].Targeting}
+		for path, key := range []string{"z", "x", "c"} {
+			if upgrade, ok := towers[0].NextUpgrade(path); ok {
+				upgrades = append(upgrades, key+" "+upgrade.Name+" "+strconv.Itoa(upgrade.Cost))
+			}
+		}
+		for i, msg := range upgrades {
+			if err := cl.renderString(msg, cl.windowW-((tileSize/2)*int32(len(msg)+1)), (cl.windowH-(tileSize*int32(len(upgrades))))+(tileSize*int32(i))); err != nil {
+				return err
+			}
+		}
+	}
+
+	if gs.State == "paused" {
 		msg := "Paused"
 		if err := cl.renderString(msg, (cl.windowW/2)-(tileSize/2)-((tileSize/2)*int32(len(msg)/2)), (cl.windowH/2)-(tileSize/2)); err != nil {
 			return err
 		}
 	}
 
-	if cl.gm.GS.Phase == "lost" {
+	if gs.Phase == "lost" {
 		msg := "Game Over"
+		if gs.Winner >= 0 {
+			msg = "P" + strconv.Itoa(gs.Winner+1) + " won"
+		}
 		if err := cl.renderString(msg, (cl.windowW/2)-(tileSize/2)-((tileSize/2)*int32(len(msg)/2)), (cl.windowH/2)-(tileSize/2)); err != nil {
 			return err
 		}
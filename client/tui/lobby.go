@@ -0,0 +1,248 @@
+package cltui
+
+import (
+	"ATowerDefense/game"
+	"ATowerDefense/server"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/HandyGold75/GOLib/tui"
+	"golang.org/x/term"
+)
+
+// Keys of the lobby, text typed is sent as chat message.
+var lobbyKeyBinds = struct{ exit, ready, confirm, delete []keybind }{
+	// ESC, CTRL_C, CTRL_D
+	exit: []keybind{{27, 0, 0}, {3, 0, 0}, {4, 0, 0}},
+	// TAB
+	ready: []keybind{{9, 0, 0}},
+	// RETURN
+	confirm: []keybind{{13, 0, 0}},
+	// BACKSPACE
+	delete: []keybind{{127, 0, 0}},
+}
+
+// Code of the game to join picked from the games on the server, 0 to create a game.
+func selectGame(client *server.Client) (int, error) {
+	games, err := client.List()
+	if err != nil {
+		return 0, err
+	}
+
+	tui.Defaults.Align = tui.AlignLeft
+	mm := tui.NewMenuBulky("ATowerDefense")
+
+	code, joinCode := -1, false
+	mm.Menu.NewAction("Create", func() { code = 0 })
+	for _, info := range games {
+		name := "Join " + strconv.Itoa(info.Code) + ": " + strconv.Itoa(len(info.Players)) + " players, " + info.Config.String()
+		if info.Started {
+			name += ", started"
+		}
+		mm.Menu.NewAction(name, func() { code = info.Code })
+	}
+	mmCode := mm.Menu.NewDigit("Code", 1000, 1000, 9999)
+	mm.Menu.NewAction("Join code", func() { joinCode = true })
+
+	if err := mm.Run(); err != nil {
+		return 0, err
+	}
+
+	if joinCode {
+		return strconv.Atoi(mmCode.Value())
+	} else if code < 0 {
+		return 0, game.Errors.Exit
+	}
+	return code, nil
+}
+
+func configureLobby(lc server.LobbyConfig) (server.LobbyConfig, error) {
+	tui.Defaults.Align = tui.AlignLeft
+	mm := tui.NewMenuBulky("ATowerDefense")
+
+	names := game.BuiltinWaveNames()
+	waveNames := []string{}
+	for i, name := range names {
+		waveNames = append(waveNames, strconv.Itoa(i)+" "+name)
+	}
+
+	mm.Menu.NewAction("Create", func() {})
+	mmFieldWidth := mm.Menu.NewDigit("Field width", min(max(lc.FieldWidth, server.MinFieldSize), server.MaxFieldSize), server.MinFieldSize, server.MaxFieldSize)
+	mmFieldHeight := mm.Menu.NewDigit("Field height", min(max(lc.FieldHeight, server.MinFieldSize), server.MaxFieldSize), server.MinFieldSize, server.MaxFieldSize)
+	mmRefundMultiplier := mm.Menu.NewDigit("Refund Multiplier", int(lc.RefundMultiplier*100), 0, 100)
+	mmSeed := mm.Menu.NewDigit("Seed, random when 0", int(min(lc.Seed, 999999999)), 0, 999999999)
+	mmWaves := mm.Menu.NewDigit("Wave set: "+strings.Join(waveNames, ", "), max(slices.Index(names, lc.Waves), 0), 0, len(names)-1)
+	economies := []string{}
+	for i, economy := range game.Economies {
+		economies = append(economies, strconv.Itoa(i)+" "+economy)
+	}
+	mmEconomy := mm.Menu.NewDigit("Economy: "+strings.Join(economies, ", "), max(slices.Index(game.Economies, lc.Economy), 0), 0, len(game.Economies)-1)
+	modes := []string{}
+	for i, mode := range game.Modes {
+		modes = append(modes, strconv.Itoa(i)+" "+mode)
+	}
+	mmMode := mm.Menu.NewDigit("Mode: "+strings.Join(modes, ", "), max(slices.Index(game.Modes, lc.Mode), 0), 0, len(game.Modes)-1)
+
+	if err := mm.Run(); err != nil {
+		return lc, err
+	}
+
+	fieldWidth, err := strconv.Atoi(mmFieldWidth.Value())
+	if err != nil {
+		return lc, err
+	}
+	lc.FieldWidth = fieldWidth
+	fieldHeight, err := strconv.Atoi(mmFieldHeight.Value())
+	if err != nil {
+		return lc, err
+	}
+	lc.FieldHeight = fieldHeight
+	refundMultiplier, err := strconv.Atoi(mmRefundMultiplier.Value())
+	if err != nil {
+		return lc, err
+	}
+	lc.RefundMultiplier = float64(refundMultiplier) / 100
+	seed, err := strconv.ParseUint(mmSeed.Value(), 10, 64)
+	if err != nil {
+		return lc, err
+	}
+	lc.Seed = seed
+	waves, err := strconv.Atoi(mmWaves.Value())
+	if err != nil {
+		return lc, err
+	}
+	lc.Waves = names[min(max(waves, 0), len(names)-1)]
+	economy, err := strconv.Atoi(mmEconomy.Value())
+	if err != nil {
+		return lc, err
+	}
+	lc.Economy = game.Economies[min(max(economy, 0), len(game.Economies)-1)]
+	mode, err := strconv.Atoi(mmMode.Value())
+	if err != nil {
+		return lc, err
+	}
+	lc.Mode = game.Modes[min(max(mode, 0), len(game.Modes)-1)]
+
+	return lc, nil
+}
+
+// Wait in the lobby of the joined game until it starts, keys pressed afterwards are passed on to the game.
+func lobby(client *server.Client) (<-chan []byte, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, errors.New("stdin is not a terminal")
+	}
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = term.Restore(int(os.Stdin.Fd()), state) }()
+
+	// Stdin is only read here, a read blocking once the lobby returns would swallow the first key of the game.
+	keys := make(chan []byte)
+	go func() {
+		defer close(keys)
+		for {
+			in := make([]byte, 3)
+			if _, err := os.Stdin.Read(in); err != nil {
+				return
+			}
+			keys <- in
+		}
+	}()
+
+	text, status, ready := "", "", false
+	for {
+		drawLobby(client, text, status, ready)
+
+		select {
+		case <-client.Started():
+			return keys, nil
+		case <-client.Done():
+			if err := client.Err(); err != nil {
+				return nil, err
+			}
+			return nil, server.Errors.Closed
+		case <-client.Updates():
+		case in, ok := <-keys:
+			if !ok || keyBindContains(lobbyKeyBinds.exit, in) {
+				return nil, game.Errors.Exit
+			}
+
+			status, err = "", nil
+			switch {
+			case keyBindContains(lobbyKeyBinds.ready, in):
+				if err = client.SetReady(!ready); err == nil {
+					ready = !ready
+				}
+			case keyBindContains(lobbyKeyBinds.confirm, in) && text == "":
+				err = client.Start()
+			case keyBindContains(lobbyKeyBinds.confirm, in):
+				err, text = client.Chat(text), ""
+			case keyBindContains(lobbyKeyBinds.delete, in):
+				if runes := []rune(text); len(runes) > 0 {
+					text = string(runes[:len(runes)-1])
+				}
+			case in[0] >= 32:
+				text += strings.TrimRight(string(in), "\x00")
+			}
+			if err != nil {
+				status = err.Error()
+			}
+		}
+	}
+}
+
+func drawLobby(client *server.Client, text, status string, ready bool) {
+	info, chat := client.Lobby()
+
+	lines := []string{
+		string(Bold) + "Game " + strconv.Itoa(info.Code) + string(Reset) + "  " + info.Config.String(),
+		"",
+	}
+	for _, player := range info.Players {
+		line := "  " + player.Name
+		if player.PID == client.PID {
+			line = "> " + player.Name
+		}
+		if player.PID == info.Host {
+			line += string(Yellow) + " host" + string(Reset)
+		} else if player.Ready {
+			line += string(Green) + " ready" + string(Reset)
+		} else {
+			line += string(Faint) + " not ready" + string(Reset)
+		}
+		lines = append(lines, line)
+	}
+	if info.Spectators > 0 {
+		lines = append(lines, string(Faint)+"  "+strconv.Itoa(info.Spectators)+" spectating"+string(Reset))
+	}
+	lines = append(lines, "")
+
+	_, mh, err := term.GetSize(int(os.Stdin.Fd()))
+	if err != nil {
+		mh = 24
+	}
+	for _, msg := range chat[max(len(chat)-max(mh-len(lines)-4, 0), 0):] {
+		lines = append(lines, string(Bold)+msg.Name+string(Reset)+": "+msg.Text)
+	}
+
+	hint := "TAB ready, RETURN chat, ESC leave"
+	if ready {
+		hint = "TAB not ready, RETURN chat, ESC leave"
+	}
+	if info.Host == client.PID {
+		hint = "RETURN chat or start when empty, ESC leave"
+	} else if client.PID < 0 {
+		hint = "Spectating, ESC leave"
+	}
+	lines = append(lines, "", "> "+text, string(Faint)+hint+string(Reset))
+	if status != "" {
+		lines = append(lines, string(Red)+status+string(Reset))
+	}
+
+	fmt.Print("\033[2J\033[H" + strings.Join(lines, "\r\n"))
+}
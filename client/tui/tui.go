@@ -2,8 +2,10 @@ package cltui
 
 import (
 	"ATowerDefense/game"
+	"ATowerDefense/server"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"slices"
 	"strconv"
@@ -19,6 +21,10 @@ type (
 
 	keybinds struct {
 		exit, pause, confirm, delete,
+		upgrade, targeting,
+		gift, nextPlayer,
+		send, nextSend,
+		save, load, exportMap,
 		up, down, right, left,
 		panUp, panDown, panRight, panLeft,
 		squereBracketLeft, squereBracketRight,
@@ -28,14 +34,25 @@ type (
 	keybind []byte
 
 	clTUI struct {
-		gm  *game.Game
-		pid int
+		gm     *game.Game
+		pid    int
+		replay bool
+		// Connected to a hosted game, see `server.Connect`.
+		remote bool
+		// Keys read by the lobby, stdin is read directly when nil.
+		keys <-chan []byte
+		// Watching a hosted game, only moving the crosshair and panning is allowed.
+		spectator bool
 
 		oldState *term.State
 
 		selectedX, selectedY,
 		viewOffsetX, viewOffsetY,
 		selectedTower int
+		// Player receiving gifts and sent enemies.
+		giftTo int
+		// Enemy sent in versus, see `game.SendTypes`.
+		selectedSend int
 
 		maxWidth, maxHeight int
 
@@ -43,6 +60,16 @@ type (
 	}
 )
 
+const (
+	saveFile   = "ATowerDefense.save"
+	replayFile = "ATowerDefense.replay"
+	mapFile    = "ATowerDefense.map"
+	// Ticks skipped per seek in replays.
+	seekTicks = 250
+	// Coins per gift.
+	giftCoins = 10
+)
+
 const (
 	Reset color = "\033[0m"
 
@@ -90,8 +117,24 @@ const (
 	BGBrightWhite   color = "\033[107m"
 )
 
+// Tower glyph colors by upgrade tier.
+var tierColors = []color{Black, Blue, Magenta, Red}
+
+// Enemy glyph colors by status effect, see `game.EffectKinds`.
+var effectColors = map[string]color{"": Red, "stun": BrightYellow, "burn": Yellow, "poison": Magenta, "slow": Cyan, "shred": BrightBlack}
+
 func Run(gc game.GameConfig) error {
-	cl, err := newTUI(gc)
+	gc, err := configure(gc)
+	if err != nil {
+		return err
+	}
+	gm := game.NewGame(gc)
+	if err := gm.Start(); err != nil {
+		return err
+	}
+	pid := gm.AddPlayer()
+
+	cl, err := newTUI(gm, pid)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -101,7 +144,67 @@ func Run(gc game.GameConfig) error {
 	return nil
 }
 
-func newTUI(gc game.GameConfig) (*clTUI, error) {
+// Join the game with code on the server on addr, pick or create a game in the lobby when code is 0.
+//
+// Spectators watch the game with code without playing.
+func Connect(addr string, code int, lc server.LobbyConfig, spectate bool) error {
+	client, err := server.Connect(addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if code == 0 {
+		code, err = selectGame(client)
+		if err == game.Errors.Exit {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	if spectate {
+		err = client.Spectate(code)
+	} else if code == 0 {
+		if lc, err = configureLobby(lc); err != nil {
+			return err
+		}
+		err = client.Create(lc)
+	} else {
+		err = client.Join(code)
+	}
+	if err != nil {
+		return err
+	}
+
+	keys, err := lobby(client)
+	if err == game.Errors.Exit {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	cl, err := newTUI(client.Game, client.PID)
+	if err != nil {
+		return err
+	}
+	cl.remote, cl.keys, cl.spectator = true, keys, spectate
+	defer cl.stop()
+	cl.start()
+	return client.Err()
+}
+
+func Replay(gm *game.Game) error {
+	cl, err := newTUI(gm, 0)
+	if err != nil {
+		return err
+	}
+	cl.replay = true
+	defer cl.stop()
+	cl.start()
+	return nil
+}
+
+func configure(gc game.GameConfig) (game.GameConfig, error) {
 	tui.Defaults.Align = tui.AlignLeft
 	mm := tui.NewMenuBulky("ASnake")
 
@@ -111,25 +214,29 @@ func newTUI(gc game.GameConfig) (*clTUI, error) {
 	mmRefundMultiplier := mm.Menu.NewDigit("Refund Multiplier", int(gc.RefundMultiplier*100), 0, 100)
 
 	if err := mm.Run(); err != nil {
-		return nil, err
+		return gc, err
 	}
 
 	fieldHeight, err := strconv.Atoi(mmFieldHeight.Value())
 	if err != nil {
-		return nil, err
+		return gc, err
 	}
 	gc.FieldHeight = fieldHeight
 	fieldWidth, err := strconv.Atoi(mmFieldWidth.Value())
 	if err != nil {
-		return nil, err
+		return gc, err
 	}
 	gc.FieldWidth = fieldWidth
 	refundMultiplier, err := strconv.Atoi(mmRefundMultiplier.Value())
 	if err != nil {
-		return nil, err
+		return gc, err
 	}
 	gc.RefundMultiplier = float64(refundMultiplier) / 100
 
+	return gc, nil
+}
+
+func newTUI(gm *game.Game, pid int) (*clTUI, error) {
 	if !term.IsTerminal(int(os.Stdin.Fd())) {
 		return nil, errors.New("stdin is not a terminal")
 	}
@@ -142,12 +249,6 @@ func newTUI(gc game.GameConfig) (*clTUI, error) {
 		return nil, err
 	}
 
-	gm := game.NewGame(gc)
-	if err := gm.Start(); err != nil {
-		return nil, err
-	}
-	pid := gm.AddPlayer()
-
 	return &clTUI{
 		gm: gm, pid: pid,
 		oldState: state,
@@ -155,6 +256,7 @@ func newTUI(gc game.GameConfig) (*clTUI, error) {
 		selectedX: 0, selectedY: 0,
 		viewOffsetX: 0, viewOffsetY: 0,
 		selectedTower: 0,
+		giftTo:        pid,
 
 		maxWidth: int(mw / 2), maxHeight: mh - 1,
 
@@ -167,6 +269,25 @@ func newTUI(gc game.GameConfig) (*clTUI, error) {
 			confirm: []keybind{{13, 0, 0}},
 			// BACKSPACE, DEL
 			delete: []keybind{{127, 0, 0}, {27, 91, 51}},
+			// Z, X, C
+			upgrade: []keybind{{122, 0, 0}, {120, 0, 0}, {99, 0, 0}},
+			// TAB
+			targeting: []keybind{{9, 0, 0}},
+			// G
+			gift: []keybind{{103, 0, 0}},
+			// N
+			nextPlayer: []keybind{{110, 0, 0}},
+			// v
+			send: []keybind{{118, 0, 0}},
+			// b
+			nextSend: []keybind{{98, 0, 0}},
+
+			// O
+			save: []keybind{{111, 0, 0}},
+			// I
+			load: []keybind{{105, 0, 0}},
+			// M
+			exportMap: []keybind{{109, 0, 0}},
 
 			// W, K
 			up: []keybind{{119, 0, 0}, {107, 0, 0}},
@@ -205,7 +326,7 @@ func newTUI(gc game.GameConfig) (*clTUI, error) {
 func (cl *clTUI) start() {
 	go func() {
 		defer cl.stop()
-		for cl.gm.GS.State != "stopped" {
+		for !cl.gm.Stopped() {
 			if err := cl.input(); err != nil {
 				if err == game.Errors.Exit {
 					break
@@ -220,15 +341,20 @@ func (cl *clTUI) start() {
 }
 
 func (cl *clTUI) stop() {
-	if cl.gm.GS.State != "stopped" {
-		_ = cl.gm.Stop()
+	// Only the first caller succeeds, the terminal is never drawn to after Stop returns.
+	if err := cl.gm.Stop(); err != nil {
+		return
 	}
 
 	if cl.oldState != nil {
 		_ = term.Restore(int(os.Stdin.Fd()), cl.oldState)
 		cl.oldState = nil
 	}
-	fmt.Print("\r\n")
+	if !cl.replay && !cl.remote {
+		if err := cl.saveReplay(); err != nil {
+			fmt.Print(err.Error() + "\r\n")
+		}
+	}
 }
 
 func (cl *clTUI) draw(processTime time.Duration) error {
@@ -238,15 +364,31 @@ func (cl *clTUI) draw(processTime time.Duration) error {
 	}
 	cl.maxWidth, cl.maxHeight = int(mw/2), mh-1
 
-	fmt.Print("\033[2J" + cl.getField() + cl.getUI(processTime) + "\033[" + strconv.Itoa(cl.maxHeight) + ";" + strconv.Itoa(cl.maxWidth*2) + "H")
+	view := cl.gm.ReadOnly()
+	fmt.Print("\033[2J" + cl.getField(view) + cl.getUI(view, processTime) + "\033[" + strconv.Itoa(cl.maxHeight) + ";" + strconv.Itoa(cl.maxWidth*2) + "H")
 	return nil
 }
 
 func (cl *clTUI) input() error {
 	in := make([]byte, 3)
-	if _, err := os.Stdin.Read(in); err != nil {
+	if cl.keys != nil {
+		key, ok := <-cl.keys
+		if !ok {
+			return game.Errors.Exit
+		}
+		in = key
+	} else if _, err := os.Stdin.Read(in); err != nil {
 		return err
 	}
+	gc := cl.config()
+
+	// Spectators only look around.
+	if cl.spectator && !slices.ContainsFunc([][]keybind{
+		cl.keyBinds.exit, cl.keyBinds.up, cl.keyBinds.down, cl.keyBinds.right, cl.keyBinds.left,
+		cl.keyBinds.panUp, cl.keyBinds.panDown, cl.keyBinds.panRight, cl.keyBinds.panLeft,
+	}, func(kb []keybind) bool { return keyBindContains(kb, in) }) {
+		return nil
+	}
 
 	if keyBindContains(cl.keyBinds.exit, in) {
 		return game.Errors.Exit
@@ -254,10 +396,11 @@ func (cl *clTUI) input() error {
 		cl.gm.TogglePause()
 		return nil
 	} else if keyBindContains(cl.keyBinds.confirm, in) {
-		if len(game.Towers) < cl.selectedTower {
+		towers := cl.towers()
+		if cl.selectedTower >= len(towers) {
 			return nil
 		}
-		if err := cl.gm.PlaceTower(game.Towers[cl.selectedTower].Name, cl.selectedX, cl.selectedY, cl.pid); err != nil {
+		if err := cl.gm.PlaceTower(towers[cl.selectedTower].Name, cl.selectedX, cl.selectedY, cl.pid); err != nil {
 			if err != game.Errors.InvalidPlacement {
 				return err
 			}
@@ -271,14 +414,40 @@ func (cl *clTUI) input() error {
 
 	} else if keyBindContains(cl.keyBinds.delete, in) {
 		return cl.gm.StartRound()
+	} else if path := keyBindIndex(cl.keyBinds.upgrade, in); path >= 0 {
+		return cl.gm.UpgradeTower(cl.selectedX, cl.selectedY, cl.pid, path)
+	} else if keyBindContains(cl.keyBinds.targeting, in) {
+		return cl.cycleTargeting()
+	} else if keyBindContains(cl.keyBinds.gift, in) {
+		if cl.giftTo == cl.pid {
+			cl.giftTo = cl.nextPlayer(cl.giftTo)
+		}
+		return cl.gm.GiftCoins(cl.pid, cl.giftTo, giftCoins)
+	} else if keyBindContains(cl.keyBinds.nextPlayer, in) {
+		cl.giftTo = cl.nextPlayer(cl.giftTo)
+		return nil
+	} else if keyBindContains(cl.keyBinds.send, in) {
+		if cl.giftTo == cl.pid {
+			cl.giftTo = cl.nextPlayer(cl.giftTo)
+		}
+		return cl.gm.SendEnemy(game.SendTypes[cl.selectedSend].Enemy, cl.pid, cl.giftTo)
+	} else if keyBindContains(cl.keyBinds.nextSend, in) {
+		cl.selectedSend = (cl.selectedSend + 1) % len(game.SendTypes)
+		return nil
+	} else if keyBindContains(cl.keyBinds.save, in) {
+		return cl.save()
+	} else if keyBindContains(cl.keyBinds.load, in) {
+		return cl.load()
+	} else if keyBindContains(cl.keyBinds.exportMap, in) {
+		return cl.exportMap()
 	} else if keyBindContains(cl.keyBinds.up, in) {
 		cl.selectedY = max(cl.selectedY-1, max(0, cl.viewOffsetY))
 		return nil
 	} else if keyBindContains(cl.keyBinds.down, in) {
-		cl.selectedY = min(cl.selectedY+1, min(cl.gm.GC.FieldHeight, min(cl.maxHeight, cl.gm.GC.FieldHeight)+cl.viewOffsetY)-1)
+		cl.selectedY = min(cl.selectedY+1, min(gc.FieldHeight, min(cl.maxHeight, gc.FieldHeight)+cl.viewOffsetY)-1)
 		return nil
 	} else if keyBindContains(cl.keyBinds.right, in) {
-		cl.selectedX = min(cl.selectedX+1, min(cl.gm.GC.FieldWidth, min(cl.maxWidth, cl.gm.GC.FieldWidth)+cl.viewOffsetX)-1)
+		cl.selectedX = min(cl.selectedX+1, min(gc.FieldWidth, min(cl.maxWidth, gc.FieldWidth)+cl.viewOffsetX)-1)
 		return nil
 	} else if keyBindContains(cl.keyBinds.left, in) {
 		cl.selectedX = max(cl.selectedX-1, max(0, cl.viewOffsetX))
@@ -289,12 +458,12 @@ func (cl *clTUI) input() error {
 		cl.selectedY = max(cl.selectedY-1, max(0, cl.viewOffsetY))
 		return nil
 	} else if keyBindContains(cl.keyBinds.panDown, in) {
-		cl.viewOffsetY = min(cl.viewOffsetY+1, (cl.gm.GC.FieldHeight-min(cl.maxHeight, cl.gm.GC.FieldHeight))+6)
-		cl.selectedY = min(cl.selectedY+1, (cl.gm.GC.FieldHeight+min(0, cl.viewOffsetY))-1)
+		cl.viewOffsetY = min(cl.viewOffsetY+1, (gc.FieldHeight-min(cl.maxHeight, gc.FieldHeight))+6)
+		cl.selectedY = min(cl.selectedY+1, (gc.FieldHeight+min(0, cl.viewOffsetY))-1)
 		return nil
 	} else if keyBindContains(cl.keyBinds.panRight, in) {
-		cl.viewOffsetX = min(cl.viewOffsetX+1, (cl.gm.GC.FieldWidth-min(cl.maxWidth, cl.gm.GC.FieldWidth))+5)
-		cl.selectedX = min(cl.selectedX+1, (cl.gm.GC.FieldWidth+min(0, cl.viewOffsetX))-1)
+		cl.viewOffsetX = min(cl.viewOffsetX+1, (gc.FieldWidth-min(cl.maxWidth, gc.FieldWidth))+5)
+		cl.selectedX = min(cl.selectedX+1, (gc.FieldWidth+min(0, cl.viewOffsetX))-1)
 		return nil
 	} else if keyBindContains(cl.keyBinds.panLeft, in) {
 		cl.viewOffsetX = max(cl.viewOffsetX-1, -5)
@@ -302,20 +471,24 @@ func (cl *clTUI) input() error {
 		return nil
 
 	} else if keyBindContains(cl.keyBinds.squereBracketLeft, in) {
+		if cl.replay {
+			return cl.seek(-seekTicks)
+		}
 		cl.selectedTower = max(cl.selectedTower-1, 0)
 		return nil
 	} else if keyBindContains(cl.keyBinds.squereBracketRight, in) {
-		cl.selectedTower = min(cl.selectedTower+1, len(game.Towers)-1)
+		if cl.replay {
+			return cl.seek(seekTicks)
+		}
+		cl.selectedTower = min(cl.selectedTower+1, len(cl.towers())-1)
 		return nil
 
 	} else if keyBindContains(cl.keyBinds.plus, in) {
-		if cl.gm.GC.TickDelay/(1<<cl.gm.GC.GameSpeed) >= time.Millisecond {
-			cl.gm.GC.GameSpeed = min(cl.gm.GC.GameSpeed+1, 9)
-		}
+		cl.gm.SetGameSpeed(gc.GameSpeed + 1)
 	} else if keyBindContains(cl.keyBinds.minus, in) {
-		cl.gm.GC.GameSpeed = max(cl.gm.GC.GameSpeed-1, 0)
+		cl.gm.SetGameSpeed(gc.GameSpeed - 1)
 	} else if i := keyBindIndex(cl.keyBinds.numbers, in); i >= 0 {
-		cl.selectedTower = max(min(i, len(game.Towers)-1), 0)
+		cl.selectedTower = max(min(i, len(cl.towers())-1), 0)
 		return nil
 
 	}
@@ -323,6 +496,92 @@ func (cl *clTUI) input() error {
 	return nil
 }
 
+func (cl *clTUI) save() error {
+	f, err := os.Create(saveFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return cl.gm.Save(f)
+}
+
+func (cl *clTUI) load() error {
+	f, err := os.Open(saveFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	if err := cl.gm.Restore(f); err != nil {
+		return err
+	}
+	// Saves may be made with different tower types.
+	cl.selectedTower = min(cl.selectedTower, len(cl.towers())-1)
+	return nil
+}
+
+func (cl *clTUI) exportMap() error {
+	f, err := os.Create(mapFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return cl.gm.ExportMap(f)
+}
+
+func (cl *clTUI) saveReplay() error {
+	f, err := os.Create(replayFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return cl.gm.SaveReplay(f)
+}
+
+func (cl *clTUI) seek(ticks int) error {
+	tick := 0
+	cl.gm.View(func() { tick = cl.gm.GS.Tick })
+	return cl.gm.Seek(max(0, tick+ticks))
+}
+
+// Switch the tower under the crosshair to the next targeting mode.
+func (cl *clTUI) cycleTargeting() error {
+	mode := ""
+	cl.gm.View(func() {
+		if towers := cl.gm.GetCollisionTowers(cl.selectedX, cl.selectedY); len(towers) == 1 {
+			mode = towers[0].Targeting
+		}
+	})
+	i := slices.Index(game.TargetingModes, mode)
+	return cl.gm.SetTargeting(cl.selectedX, cl.selectedY, cl.pid, game.TargetingModes[(i+1)%len(game.TargetingModes)])
+}
+
+// Player after pid besides the own player, the own player when playing alone.
+func (cl *clTUI) nextPlayer(pid int) int {
+	players := 0
+	cl.gm.View(func() { players = len(cl.gm.Players) })
+	if players < 2 {
+		return cl.pid
+	}
+	if pid = (pid + 1) % players; pid == cl.pid {
+		pid = (pid + 1) % players
+	}
+	return pid
+}
+
+// Settings of the game, safe to call outside of draw.
+func (cl *clTUI) config() game.GameConfig {
+	gc := game.GameConfig{}
+	cl.gm.View(func() { gc = cl.gm.GC })
+	return gc
+}
+
+// Tower types that can be placed, safe to call outside of draw.
+func (cl *clTUI) towers() []game.TowerType {
+	towers := []game.TowerType{}
+	cl.gm.View(func() { towers = cl.gm.GC.Towers })
+	return towers
+}
+
 func keyBindContains(kb []keybind, b []byte) bool {
 	return slices.ContainsFunc(kb, func(v keybind) bool { return slices.Equal(v, b) })
 }
@@ -331,31 +590,40 @@ func keyBindIndex(kb []keybind, b []byte) int {
 	return slices.IndexFunc(kb, func(v keybind) bool { return slices.Equal(v, b) })
 }
 
-func (cl *clTUI) getField() string {
+func (cl *clTUI) getField(view game.Viewer) string {
+	gc, gs := view.GetConfig(), view.GetState()
+
+	projectiles := map[[2]int]bool{}
+	for _, projectile := range gs.Projectiles {
+		projectiles[[2]int{int(math.Round(projectile.X)), int(math.Round(projectile.Y))}] = true
+	}
+
 	frame := "\033[2;0H"
-	for y := range min(cl.gm.GC.FieldHeight, cl.maxHeight) {
+	for y := range min(gc.FieldHeight, cl.maxHeight) {
 		if y != 0 {
 			frame += "\r\n"
 		}
-		if y+cl.viewOffsetY < 0 || y+cl.viewOffsetY >= cl.gm.GC.FieldHeight {
-			frame += strings.Repeat(string(BGBrightBlack+BrightBlack+"  "+Reset), min(cl.gm.GC.FieldWidth, cl.maxWidth))
+		if y+cl.viewOffsetY < 0 || y+cl.viewOffsetY >= gc.FieldHeight {
+			frame += strings.Repeat(string(BGBrightBlack+BrightBlack+"  "+Reset), min(gc.FieldWidth, cl.maxWidth))
 			continue
 		}
-		for x := range min(cl.gm.GC.FieldWidth, cl.maxWidth) {
-			if x+cl.viewOffsetX < 0 || x+cl.viewOffsetX >= cl.gm.GC.FieldWidth {
+		for x := range min(gc.FieldWidth, cl.maxWidth) {
+			if x+cl.viewOffsetX < 0 || x+cl.viewOffsetX >= gc.FieldWidth {
 				frame += string(BGBrightBlack + BrightBlack + "  " + Reset)
 			} else if x+cl.viewOffsetX == cl.selectedX && y+cl.viewOffsetY == cl.selectedY {
 				frame += string(BGGreen + Black + "" + Reset)
-			} else if objects := cl.gm.GetCollisions(x+cl.viewOffsetX, y+cl.viewOffsetY); len(objects) > 0 {
+			} else if projectiles[[2]int{x + cl.viewOffsetX, y + cl.viewOffsetY}] && !view.CheckCollisionTowers(x+cl.viewOffsetX, y+cl.viewOffsetY) {
+				frame += string(BGGreen + BrightYellow + " •" + Reset)
+			} else if objects := view.GetCollisions(x+cl.viewOffsetX, y+cl.viewOffsetY); len(objects) > 0 {
 				switch obj := objects[len(objects)-1].(type) {
 				case *game.ObstacleObj:
 					frame += string(BGBrightYellow + BrightBlue + "" + Reset)
 
 				case *game.RoadObj:
-					if obj.Index == 0 {
+					if obj.DirEntrance == "start" {
 						frame += string(BGGreen + White + BrightBlack + " 󰮢" + Reset)
 						continue
-					} else if obj.Index == len(cl.gm.GS.Roads)-1 {
+					} else if obj.DirExit == "end" {
 						frame += string(BGGreen + White + BrightBlack + " 󰄚" + Reset)
 						continue
 					}
@@ -374,14 +642,18 @@ func (cl *clTUI) getField() string {
 					}
 
 				case *game.TowerObj:
-					frame += string(BGGreen + Black + " 󰚁" + Reset)
+					if obj.Disabled > 0 {
+						frame += string(BGGreen+BrightBlack) + obj.Glyph + string(Reset)
+						continue
+					}
+					frame += string(BGGreen+tierColors[min(obj.Tier, len(tierColors)-1)]) + obj.Glyph + string(Reset)
 
 				case *game.EnemyObj:
 					if obj.Progress < 1 {
 						frame += string(BGGreen + Red + BrightBlack + " 󰮢" + Reset)
 						continue
 					}
-					frame += string(BGGreen + Red + " " + Reset)
+					frame += string(BGGreen+effectColors[obj.Effect()]) + obj.EnemyType().Glyph + string(Reset)
 
 				default:
 					frame += string(BGBrightMagenta + BrightMagenta + "??" + Reset)
@@ -394,31 +666,46 @@ func (cl *clTUI) getField() string {
 	return frame
 }
 
-func (cl *clTUI) getUI(processTime time.Duration) string {
-	phase := cl.gm.GS.Phase
-	if cl.gm.GS.State == "paused" {
+func (cl *clTUI) getUI(view game.Viewer, processTime time.Duration) string {
+	gc, gs, players := view.GetConfig(), view.GetState(), view.GetPlayers()
+
+	phase := gs.Phase
+	if gs.State == "paused" {
 		phase += " [p]"
 	}
-	phase += " R:" + strconv.Itoa(cl.gm.GS.Round)
-	if cl.gm.GS.Phase == "defending" {
-		phase += " E:" + strconv.Itoa(len(cl.gm.GS.Enemies))
+	phase += " R:" + strconv.Itoa(gs.Round)
+	if gs.Phase == "defending" {
+		phase += " E:" + strconv.Itoa(len(gs.Enemies))
+	} else if gs.Phase == "lost" && gs.Winner >= 0 {
+		phase += " P" + strconv.Itoa(gs.Winner+1) + " won"
+	}
+	if cl.replay {
+		phase += " " + (time.Duration(gs.Tick) * game.TickDuration).Truncate(time.Second).String()
+	}
+	if cl.spectator {
+		phase += " [s]"
 	}
 	msgLen := len(phase)
 	msgLeft := fmt.Sprintf(string(BrightWhite+"%v"), phase)
+	if boss := view.Boss(); boss != nil {
+		filled := int(math.Ceil(float64(boss.Health) / float64(boss.StartHealth) * 10))
+		msgLen += 13
+		msgLeft += string(BrightRed) + " B " + string(BGRed) + strings.Repeat(" ", filled) + string(BGBlack) + strings.Repeat(" ", 10-filled) + string(BGBrightBlack)
+	}
 
 	lag := strconv.FormatInt(processTime.Milliseconds(), 10)
-	if processTime >= cl.gm.GC.TickDelay/time.Duration(1<<max(0, cl.gm.GC.GameSpeed-1)) {
+	if processTime >= gc.TickDelay {
 		msgLen -= 4
 		lag = string(Red) + lag
 	}
-	msgLen += len(lag) + len(strconv.Itoa(cl.gm.GC.GameSpeed)) + len(strconv.Itoa(cl.gm.Players[cl.pid].Coins)) + len(strconv.Itoa(cl.gm.GS.Health)) + 3
-	msgRight := fmt.Sprintf(string(White+"%v "+White+"%v "+BrightYellow+"%v "+BrightRed+"%v"), lag, cl.gm.GC.GameSpeed, cl.gm.Players[cl.pid].Coins, cl.gm.GS.Health)
+	msgLen += len(lag) + len(strconv.Itoa(gc.GameSpeed)) + len(strconv.Itoa(view.GetCoins(cl.pid))) + len(strconv.Itoa(view.GetHealth(cl.pid))) + 3
+	msgRight := fmt.Sprintf(string(White+"%v "+White+"%v "+BrightYellow+"%v "+BrightRed+"%v"), lag, gc.GameSpeed, view.GetCoins(cl.pid), view.GetHealth(cl.pid))
 
-	frame := fmt.Sprintf("\033[0;0H"+string(BGBrightBlack)+"%v"+strings.Repeat(" ", max(1, min(cl.gm.GC.FieldWidth*2, cl.maxWidth*2)-msgLen))+"%v"+string(Reset), msgLeft, msgRight)
+	frame := fmt.Sprintf("\033[0;0H"+string(BGBrightBlack)+"%v"+strings.Repeat(" ", max(1, min(gc.FieldWidth*2, cl.maxWidth*2)-msgLen))+"%v"+string(Reset), msgLeft, msgRight)
 
-	if cl.maxWidth > cl.gm.GC.FieldWidth+10 && cl.maxHeight+1 >= len(game.Towers) {
-		for i, tower := range game.Towers {
-			frame += "\033[" + strconv.Itoa(i+1) + ";" + strconv.Itoa((cl.gm.GC.FieldWidth*2)+1) + "H"
+	if cl.maxWidth > gc.FieldWidth+10 && cl.maxHeight+1 >= len(gc.Towers) {
+		for i, tower := range gc.Towers {
+			frame += "\033[" + strconv.Itoa(i+1) + ";" + strconv.Itoa((gc.FieldWidth*2)+1) + "H"
 			msgLeft := tower.Name
 			msgRight := "(" + strconv.Itoa(tower.Cost) + ")"
 			if i == cl.selectedTower {
@@ -427,6 +714,69 @@ func (cl *clTUI) getUI(processTime time.Duration) string {
 				frame += string(BGBlack+White) + msgLeft + strings.Repeat(" ", max(0, 20-len(msgLeft)-len(msgRight))) + msgRight + string(Reset)
 			}
 		}
+
+		row := len(gc.Towers) + 2
+		if towers := view.GetCollisionTowers(cl.selectedX, cl.selectedY); len(towers) == 1 && towers[0].Owner == cl.pid {
+			if row <= cl.maxHeight+1 {
+				frame += "\033[" + strconv.Itoa(row) + ";" + strconv.Itoa((gc.FieldWidth*2)+1) + "H"
+				msgLeft, msgRight := "tab", towers[0].Targeting
+				frame += string(BGBlack+BrightCyan) + msgLeft + strings.Repeat(" ", max(0, 20-len(msgLeft)-len(msgRight))) + msgRight + string(Reset)
+				row++
+			}
+			for path, key := range []string{"z", "x", "c"} {
+				upgrade, ok := towers[0].NextUpgrade(path)
+				if !ok || row > cl.maxHeight+1 {
+					continue
+				}
+				frame += "\033[" + strconv.Itoa(row) + ";" + strconv.Itoa((gc.FieldWidth*2)+1) + "H"
+				msgRight := "(" + strconv.Itoa(upgrade.Cost) + ")"
+				msgLeft := key + " " + upgrade.Name
+				msgLeft = msgLeft[:min(len(msgLeft), 19-len(msgRight))]
+				frame += string(BGBlack+BrightYellow) + msgLeft + strings.Repeat(" ", max(0, 20-len(msgLeft)-len(msgRight))) + msgRight + string(Reset)
+				row++
+			}
+			row++
+		}
+
+		if gc.Mode == "versus" && !cl.spectator && row <= cl.maxHeight+1 {
+			sendType := game.SendTypes[cl.selectedSend]
+			frame += "\033[" + strconv.Itoa(row) + ";" + strconv.Itoa((gc.FieldWidth*2)+1) + "H"
+			msgLeft, msgRight := "v "+sendType.Enemy, "("+strconv.Itoa(sendType.Cost)+")"
+			frame += string(BGBlack+BrightRed) + msgLeft + strings.Repeat(" ", max(0, 20-len(msgLeft)-len(msgRight))) + msgRight + string(Reset)
+			row += 2
+		}
+
+		// Stats per player with several players or spectating, the player receiving gifts and sent enemies is marked with g.
+		for i, player := range players {
+			if (len(players) < 2 && !cl.spectator) || row+1 > cl.maxHeight+1 {
+				break
+			}
+			clr := White
+			if i == cl.pid {
+				clr = BrightYellow
+			}
+			msgLeft, msgRight := "P"+strconv.Itoa(i+1), strconv.Itoa(view.GetCoins(i))+" "+strconv.Itoa(player.Stats.Kills)+"k"
+			if i == cl.giftTo && i != cl.pid {
+				msgLeft += " g"
+			}
+			frame += "\033[" + strconv.Itoa(row) + ";" + strconv.Itoa((gc.FieldWidth*2)+1) + "H"
+			frame += string(BGBlack+clr) + msgLeft + strings.Repeat(" ", max(0, 20-len(msgLeft)-len(msgRight))) + msgRight + string(Reset)
+			// Net coins gifted, received is positive.
+			msgLeft, msgRight = " dmg "+strconv.Itoa(player.Stats.Damage), strconv.Itoa(player.Stats.Received-player.Stats.Gifted)
+			if player.Stats.Received > player.Stats.Gifted {
+				msgRight = "+" + msgRight
+			}
+			// Health and income per round in versus.
+			if gc.Mode == "versus" {
+				msgLeft, msgRight = " hp "+strconv.Itoa(player.Health), "+"+strconv.Itoa(player.Income)
+				if player.Phase == "lost" {
+					msgLeft = " lost"
+				}
+			}
+			frame += "\033[" + strconv.Itoa(row+1) + ";" + strconv.Itoa((gc.FieldWidth*2)+1) + "H"
+			frame += string(BGBlack+Faint+clr) + msgLeft + strings.Repeat(" ", max(0, 20-len(msgLeft)-len(msgRight))) + msgRight + string(Reset)
+			row += 2
+		}
 	}
 	return frame
 }
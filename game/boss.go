@@ -0,0 +1,108 @@
+package game
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+)
+
+type (
+	BossWave struct {
+		// Rounds divisible by this end with a boss.
+		Every int `json:"every"`
+		// Expressions of the round `r`, see `EndlessGroup`.
+		Health string `json:"health"`
+		Speed  string `json:"speed"`
+		Reward string `json:"reward"`
+		// Delay after the last enemy of the round in ms.
+		Delay string `json:"delay,omitempty"`
+		// Triggered in order once the boss health drops below their threshold.
+		Phases []BossPhase `json:"phases,omitempty"`
+	}
+	BossPhase struct {
+		// Part of the starting health below which the phase triggers, in range (0, 1).
+		Health float64 `json:"health"`
+		// Valid abilities: see `BossAbilities`
+		Ability string `json:"ability"`
+		// Burst and disable: duration in ms.
+		Duration int `json:"duration,omitempty"`
+		// Minions: amount of basic enemies spawned.
+		Count int `json:"count,omitempty"`
+	}
+)
+
+// Valid boss phase abilities.
+//
+// Burst doubles the boss speed, minions spawns basic enemies around the boss and disable stops the nearest tower.
+var BossAbilities = []string{"burst", "minions", "disable"}
+
+func (boss *BossWave) group(r int, rng *rand.Rand) (WaveGroup, error) {
+	return EndlessGroup{Type: "boss", Count: "1", Health: boss.Health, Speed: boss.Speed, Reward: boss.Reward, Delay: boss.Delay}.eval(r, rng)
+}
+
+func (boss *BossWave) validate() error {
+	if boss.Every < 1 {
+		return errors.New("every is less than 1")
+	}
+	for i, phase := range boss.Phases {
+		prefix := "phases[" + strconv.Itoa(i) + "]: "
+		switch {
+		case phase.Health <= 0 || phase.Health >= 1:
+			return errors.New(prefix + "health is not in range (0, 1)")
+		case i > 0 && phase.Health >= boss.Phases[i-1].Health:
+			return errors.New(prefix + "health is not below the previous phase")
+		case !slices.Contains(BossAbilities, phase.Ability):
+			return errors.New(prefix + "ability " + strconv.Quote(phase.Ability) + " is unknown")
+		case phase.Ability != "minions" && phase.Duration <= 0:
+			return errors.New(prefix + "duration is not positive")
+		case phase.Ability == "minions" && phase.Count <= 0:
+			return errors.New(prefix + "count is not positive")
+		}
+	}
+	return nil
+}
+
+// First boss on the field, nil when there is none.
+func (game *Game) Boss() *EnemyObj {
+	i := slices.IndexFunc(game.GS.Enemies, func(obj *EnemyObj) bool { return obj.EnemyType().Boss && obj.startDelay <= 0 })
+	if i < 0 {
+		return nil
+	}
+	return game.GS.Enemies[i]
+}
+
+// Trigger every phase the boss health dropped below since the last hit.
+func (game *Game) bossPhases(enemy *EnemyObj) {
+	if game.GC.Waves == nil || game.GC.Waves.Boss == nil || !enemy.EnemyType().Boss {
+		return
+	}
+	phases := game.GC.Waves.Boss.Phases
+	for enemy.phase < len(phases) && float64(enemy.Health) < phases[enemy.phase].Health*float64(enemy.StartHealth) {
+		phase := phases[enemy.phase]
+		enemy.phase++
+
+		switch phase.Ability {
+		case "burst":
+			enemy.burst = max(enemy.burst, phase.Duration)
+		case "minions":
+			game.spawnBehind(enemy, EnemyTypes[0], phase.Count, max(1, enemy.StartHealth/20), 1)
+		case "disable":
+			var nearest *TowerObj
+			distance := func(obj *TowerObj) int {
+				return ((obj.x - enemy.x) * (obj.x - enemy.x)) + ((obj.y - enemy.y) * (obj.y - enemy.y))
+			}
+			for _, tower := range game.GS.Towers {
+				if game.GC.Mode == "versus" && game.laneOf(tower.x) != game.laneOf(enemy.x) {
+					continue
+				}
+				if nearest == nil || distance(tower) < distance(nearest) {
+					nearest = tower
+				}
+			}
+			if nearest != nil {
+				nearest.Disabled = max(nearest.Disabled, phase.Duration)
+			}
+		}
+	}
+}
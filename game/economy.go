@@ -0,0 +1,77 @@
+package game
+
+// Valid economies, the first is used when `GameConfig.Economy` is not set.
+//
+// Owner rewards kills to the owner of the tower, split divides rewards evenly over every player,
+// shared pools the coins of every player in one wallet.
+var Economies = []string{"owner", "split", "shared"}
+
+// Coins player pid spends from and is refunded to, the wallet of the first player in the shared economy.
+func (game *Game) wallet(pid int) *int {
+	if game.GC.Economy == "shared" {
+		return &game.Players[0].Coins
+	}
+	return &game.Players[pid].Coins
+}
+
+// Coins player pid can spend.
+func (game *Game) GetCoins(pid int) int {
+	if pid < 0 || pid >= len(game.Players) {
+		return 0
+	}
+	return *game.wallet(pid)
+}
+
+func (game *Game) spend(pid, coins int) {
+	*game.wallet(pid) -= coins
+	game.Players[pid].Stats.CoinsSpent += coins
+}
+
+// Hand out the reward of an enemy defeated by a tower of owner.
+func (game *Game) reward(coins, owner int) {
+	if owner < 0 || owner >= len(game.Players) {
+		return
+	}
+	game.Players[owner].Stats.Kills++
+
+	if game.GC.Economy != "split" {
+		*game.wallet(owner) += coins
+		game.Players[owner].Stats.CoinsEarned += coins
+		return
+	}
+	// The remainder goes to the owner first and the players after it.
+	share, rest := coins/len(game.Players), coins%len(game.Players)
+	for i := range game.Players {
+		earned := share
+		if (i-owner+len(game.Players))%len(game.Players) < rest {
+			earned++
+		}
+		game.Players[i].Coins += earned
+		game.Players[i].Stats.CoinsEarned += earned
+	}
+}
+
+func (game *Game) GiftCoins(pid, to, coins int) error {
+	return game.command(Command{Kind: "gift", PID: pid, Target: to, Value: coins})
+}
+
+func (game *Game) giftCoins(pid, to, coins int) error {
+	if game.GS.State != "started" && game.GS.State != "paused" {
+		return Errors.GameStateNotActive
+	}
+	if pid < 0 || pid >= len(game.Players) || to < 0 || to >= len(game.Players) || pid == to {
+		return Errors.InvalidPlayer
+	}
+	if coins <= 0 || game.GC.Economy == "shared" || game.GC.Mode == "versus" {
+		return Errors.InvalidGift
+	}
+	if coins > game.Players[pid].Coins {
+		return Errors.InsufficientFunds
+	}
+
+	game.Players[pid].Coins -= coins
+	game.Players[to].Coins += coins
+	game.Players[pid].Stats.Gifted += coins
+	game.Players[to].Stats.Received += coins
+	return nil
+}
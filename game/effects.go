@@ -0,0 +1,144 @@
+package game
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+type (
+	Effect struct {
+		// Valid kinds: see `EffectKinds`
+		Kind string `json:"kind"`
+		// Duration in ms.
+		Duration int `json:"duration"`
+		// Slow: part of the speed lost, poison and burn: damage every second, shred: extra damage taken per hit.
+		Strength float64 `json:"strength,omitempty"`
+	}
+	StatusEffect struct {
+		Effect
+		// Remaining duration in ms.
+		Remaining int
+		// Name and owner of the tower that applied it, credited for damage over time.
+		Tower string
+		Owner int
+		// Damage over time not dealt yet.
+		pending float64
+	}
+)
+
+// Stacks of poison and shred at most, other kinds never stack.
+const maxEffectStacks = 5
+
+// Valid effect kinds, ordered by which is shown when an enemy has several.
+var EffectKinds = []string{"stun", "burn", "poison", "slow", "shred"}
+
+func (effect Effect) validate() error {
+	switch {
+	case !slices.Contains(EffectKinds, effect.Kind):
+		return errors.New("kind is unknown")
+	case effect.Duration <= 0:
+		return errors.New("duration is not positive")
+	case effect.Kind == "slow" && (effect.Strength <= 0 || effect.Strength > 1):
+		return errors.New("strength is not in range (0, 1]")
+	case effect.Kind != "stun" && effect.Kind != "slow" && effect.Strength <= 0:
+		return errors.New("strength is not positive")
+	}
+	return nil
+}
+
+// Poison and shred stack, replacing the stack closest to expiring once full.
+// Other kinds refresh the existing effect, keeping the longest duration and strongest strength.
+func (obj *EnemyObj) applyEffect(effect Effect, tower string, owner int) {
+	status := StatusEffect{Effect: effect, Remaining: effect.Duration, Tower: tower, Owner: owner}
+
+	if effect.Kind == "poison" || effect.Kind == "shred" {
+		stacks := []int{}
+		for i, obj := range obj.Effects {
+			if obj.Kind == effect.Kind {
+				stacks = append(stacks, i)
+			}
+		}
+		if len(stacks) < maxEffectStacks {
+			obj.Effects = append(obj.Effects, status)
+			return
+		}
+		i := slices.MinFunc(stacks, func(a, b int) int { return obj.Effects[a].Remaining - obj.Effects[b].Remaining })
+		obj.Effects[i] = status
+		return
+	}
+
+	i := slices.IndexFunc(obj.Effects, func(obj StatusEffect) bool { return obj.Kind == effect.Kind })
+	if i < 0 {
+		obj.Effects = append(obj.Effects, status)
+		return
+	}
+	if effect.Strength >= obj.Effects[i].Strength {
+		obj.Effects[i].Strength, obj.Effects[i].Tower, obj.Effects[i].Owner = effect.Strength, tower, owner
+	}
+	obj.Effects[i].Remaining = max(obj.Effects[i].Remaining, effect.Duration)
+}
+
+// Multiplier of the enemy speed, 0 when stunned and doubled by a boss burst.
+func (obj *EnemyObj) speedFactor() float64 {
+	factor, slow := 1.0, 0.0
+	if obj.burst > 0 {
+		factor = 2
+	}
+	for _, effect := range obj.Effects {
+		switch effect.Kind {
+		case "stun":
+			return 0
+		case "slow":
+			slow = max(slow, effect.Strength)
+		}
+	}
+	return factor * (1 - slow)
+}
+
+// Extra damage taken per hit.
+func (obj *EnemyObj) shred() int {
+	shred := 0.0
+	for _, effect := range obj.Effects {
+		if effect.Kind == "shred" {
+			shred += effect.Strength
+		}
+	}
+	return int(shred)
+}
+
+// Kind of the most important active effect for renderers, empty when none.
+func (obj *EnemyObj) Effect() string {
+	for _, kind := range EffectKinds {
+		if slices.ContainsFunc(obj.Effects, func(obj StatusEffect) bool { return obj.Kind == kind }) {
+			return kind
+		}
+	}
+	return ""
+}
+
+func (game *Game) tickEffects(delta time.Duration) {
+	for _, enemy := range slices.Clone(game.GS.Enemies) {
+		if enemy.startDelay > 0 {
+			continue
+		}
+
+		for i := range enemy.Effects {
+			effect := &enemy.Effects[i]
+			effect.Remaining -= int(delta.Milliseconds())
+			if effect.Kind != "poison" && effect.Kind != "burn" {
+				continue
+			}
+
+			effect.pending += effect.Strength * delta.Seconds()
+			if damage := int(effect.pending); damage > 0 {
+				effect.pending -= float64(damage)
+				game.damageEnemy(enemy, damage, effect.Tower, effect.Owner)
+				if enemy.Health <= 0 {
+					break
+				}
+			}
+		}
+		enemy.Effects = slices.DeleteFunc(enemy.Effects, func(obj StatusEffect) bool { return obj.Remaining <= 0 })
+	}
+}
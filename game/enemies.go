@@ -0,0 +1,121 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+type EnemyType struct {
+	// Unique enemy name, used by wave groups.
+	Name string
+	// Multiplies the health and speed of wave groups.
+	HealthMultiplier, SpeedMultiplier float64
+	// Subtracted from the damage of every hit, hits always deal at least 1 damage.
+	Armour int
+	// Part of the starting health added as shield, absorbing damage before health.
+	Shield float64
+	// Part of the starting health restored every second.
+	Regen float64
+	// Part of the starting health of the healer restored to other enemies in range every second.
+	Heal float64
+	// Range in tiles of heal.
+	HealRange float64
+	// Basic enemies spawned once defeated, with half the starting health and reward.
+	Split int
+	// Only targeted by towers with `TowerType.Detection`.
+	Stealth bool
+	// Goes through the phases of `BossWave`.
+	Boss bool
+	// Row in the enemies texture.
+	Sprite int
+	// Drawn by terminal renderers, 2 cells wide.
+	Glyph string
+}
+
+// Valid enemy types of wave groups.
+var EnemyTypes = []EnemyType{
+	{Name: "basic", HealthMultiplier: 1, SpeedMultiplier: 1, Sprite: 0, Glyph: " "},
+	{Name: "armoured", HealthMultiplier: 1, SpeedMultiplier: 0.75, Armour: 2, Sprite: 1, Glyph: " "},
+	{Name: "shielded", HealthMultiplier: 1, SpeedMultiplier: 1, Shield: 0.5, Sprite: 2, Glyph: " "},
+	{Name: "runner", HealthMultiplier: 0.5, SpeedMultiplier: 2, Sprite: 3, Glyph: " "},
+	{Name: "regen", HealthMultiplier: 1, SpeedMultiplier: 1, Regen: 0.1, Sprite: 4, Glyph: " "},
+	{Name: "splitter", HealthMultiplier: 1, SpeedMultiplier: 0.9, Split: 2, Sprite: 5, Glyph: " "},
+	{Name: "healer", HealthMultiplier: 0.75, SpeedMultiplier: 1, Heal: 0.1, HealRange: 2, Sprite: 6, Glyph: " "},
+	{Name: "stealth", HealthMultiplier: 0.75, SpeedMultiplier: 1.25, Stealth: true, Sprite: 7, Glyph: " "},
+	{Name: "boss", HealthMultiplier: 1, SpeedMultiplier: 1, Armour: 1, Boss: true, Sprite: 8, Glyph: " "},
+}
+
+func enemyType(name string) (EnemyType, bool) {
+	i := slices.IndexFunc(EnemyTypes, func(obj EnemyType) bool { return obj.Name == name })
+	if i < 0 {
+		return EnemyType{}, false
+	}
+	return EnemyTypes[i], true
+}
+
+// Type definition of the enemy, basic when unknown.
+func (obj *EnemyObj) EnemyType() EnemyType {
+	if enemyType, ok := enemyType(obj.Type); ok {
+		return enemyType
+	}
+	return EnemyTypes[0]
+}
+
+func (game *Game) newEnemy(enemyType EnemyType, x, y, health, reward, startDelay int, speed float64) *EnemyObj {
+	health = max(1, int(float64(health)*enemyType.HealthMultiplier))
+	return &EnemyObj{
+		x: x, y: y, UID: game.newUID(), Progress: 0.0,
+		Type:   enemyType.Name,
+		Health: health, StartHealth: health,
+		Shield:          int(float64(health) * enemyType.Shield),
+		reward:          reward,
+		startDelay:      startDelay,
+		speedMultiplier: speed * enemyType.SpeedMultiplier,
+	}
+}
+
+// Spawn enemies in place of another, slightly behind each other.
+func (game *Game) spawnBehind(enemy *EnemyObj, enemyType EnemyType, count, health, reward int) {
+	for i := range count {
+		child := game.newEnemy(enemyType, enemy.x, enemy.y, health, reward, 0, enemy.speedMultiplier)
+		child.Progress = max(enemy.Progress-(float64(i)*0.25), min(enemy.Progress, 1))
+		child.lastProgress = child.Progress
+		child.Route = enemy.Route
+		game.GS.Enemies = append(game.GS.Enemies, child)
+	}
+}
+
+func (game *Game) tickAbilities(delta time.Duration) {
+	for _, enemy := range game.GS.Enemies {
+		if enemy.startDelay > 0 {
+			continue
+		}
+		enemyType := enemy.EnemyType()
+		enemy.burst = max(0, enemy.burst-int(delta.Milliseconds()))
+
+		if enemyType.Regen > 0 && enemy.Health < enemy.StartHealth {
+			enemy.regenPending += enemyType.Regen * float64(enemy.StartHealth) * delta.Seconds()
+			if health := int(enemy.regenPending); health > 0 {
+				enemy.regenPending -= float64(health)
+				enemy.Health = min(enemy.StartHealth, enemy.Health+health)
+			}
+		}
+
+		if enemyType.Heal <= 0 {
+			continue
+		}
+		enemy.healPending += enemyType.Heal * float64(enemy.StartHealth) * delta.Seconds()
+		health := int(enemy.healPending)
+		if health <= 0 {
+			continue
+		}
+		enemy.healPending -= float64(health)
+		for _, obj := range game.GS.Enemies {
+			if obj == enemy || obj.startDelay > 0 || math.Hypot(float64(obj.x-enemy.x), float64(obj.y-enemy.y)) > enemyType.HealRange {
+				continue
+			}
+			obj.Health = min(obj.StartHealth, obj.Health+health)
+		}
+	}
+}
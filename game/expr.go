@@ -0,0 +1,202 @@
+package game
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+)
+
+// Evaluates expressions of endless waves while parsing them.
+//
+// Supports numbers, the round `r`, `+ - * / %`, parentheses and the functions `min`, `max`, `int` and `rand`.
+type exprParser struct {
+	src string
+	pos int
+	r   float64
+	rng *rand.Rand
+}
+
+func evalExpr(src string, r int, rng *rand.Rand) (float64, error) {
+	p := &exprParser{src: src, r: float64(r), rng: rng}
+	v, err := p.expr()
+	if err != nil {
+		return 0, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return 0, p.error("unexpected " + strconv.Quote(p.src[p.pos:p.pos+1]))
+	}
+	// Results are converted to ints, which is undefined out of this range.
+	if math.IsNaN(v) {
+		return 0, nil
+	}
+	return min(max(v, -math.MaxInt32), math.MaxInt32), nil
+}
+
+func (p *exprParser) error(msg string) error {
+	return errors.New("expression " + strconv.Quote(p.src) + ": " + msg + " at " + strconv.Itoa(p.pos))
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *exprParser) consume(c byte) bool {
+	if p.skipSpace(); p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expr() (float64, error) {
+	v, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.consume('+'):
+			t, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v += t
+		case p.consume('-'):
+			t, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			v -= t
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *exprParser) term() (float64, error) {
+	v, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.consume('*'):
+			t, err := p.unary()
+			if err != nil {
+				return 0, err
+			}
+			v *= t
+		case p.consume('/'):
+			t, err := p.unary()
+			if err != nil {
+				return 0, err
+			} else if t == 0 {
+				// Division by zero is 0, expressions hold for every round.
+				v = 0
+				continue
+			}
+			v /= t
+		case p.consume('%'):
+			t, err := p.unary()
+			if err != nil {
+				return 0, err
+			} else if t == 0 {
+				v = 0
+				continue
+			}
+			v = math.Mod(v, t)
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *exprParser) unary() (float64, error) {
+	if p.consume('-') {
+		v, err := p.unary()
+		return -v, err
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (float64, error) {
+	if p.consume('(') {
+		v, err := p.expr()
+		if err != nil {
+			return 0, err
+		}
+		if !p.consume(')') {
+			return 0, p.error("expected \")\"")
+		}
+		return v, nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	if p.pos < len(p.src) && (p.src[p.pos] == '.' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		for p.pos < len(p.src) && (p.src[p.pos] == '.' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return 0, p.error("invalid number")
+		}
+		return v, nil
+	}
+
+	for p.pos < len(p.src) && p.src[p.pos] >= 'a' && p.src[p.pos] <= 'z' {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		if p.pos >= len(p.src) {
+			return 0, p.error("unexpected end")
+		}
+		return 0, p.error("unexpected " + strconv.Quote(p.src[p.pos:p.pos+1]))
+	}
+	if name == "r" {
+		return p.r, nil
+	}
+
+	if !p.consume('(') {
+		p.pos = start
+		return 0, p.error("unknown variable " + strconv.Quote(name))
+	}
+	args := []float64{}
+	if !p.consume(')') {
+		for {
+			v, err := p.expr()
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, v)
+			if p.consume(')') {
+				break
+			}
+			if !p.consume(',') {
+				return 0, p.error("expected \",\" or \")\"")
+			}
+		}
+	}
+
+	switch {
+	case name == "min" && len(args) > 0:
+		return slices.Min(args), nil
+	case name == "max" && len(args) > 0:
+		return slices.Max(args), nil
+	case name == "int" && len(args) == 1:
+		return math.Trunc(args[0]), nil
+	case name == "rand" && len(args) == 0:
+		return p.rng.Float64(), nil
+	case name == "min" || name == "max" || name == "int" || name == "rand":
+		p.pos = start
+		return 0, p.error("wrong number of arguments for " + strconv.Quote(name))
+	}
+	p.pos = start
+	return 0, p.error("unknown function " + strconv.Quote(name))
+}
@@ -5,6 +5,8 @@ import (
 	"math"
 	"math/rand/v2"
 	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -13,8 +15,13 @@ type (
 		GameStateNotWaiting, GameStateNotActive, GamePhaseNotBuilding,
 		GamePhaseStopped,
 		InvalidPlacement, InvalidSelection, InvalidPlayer,
-		TowerNotExists,
-		InsufficientFunds,
+		TowerNotExists, WavesNotExists, UpgradeNotExists,
+		InvalidTargeting, InvalidMode, InvalidEconomy,
+		InsufficientFunds, InvalidGift, PathBlocked,
+		InvalidSave, InvalidReplay, InvalidCommand,
+		ReplayReadOnly, NotReplay,
+		RemoteGame, NotRemote,
+		NotEnoughPlayers, FieldTooNarrow,
 		Exit error
 	}
 
@@ -24,6 +31,22 @@ type (
 		GameSpeed        int
 		RefundMultiplier float64
 		TickDelay        time.Duration
+		// Seed for the game RNG, same seed and inputs produce the same game.
+		Seed uint64
+		// Tower types that can be placed, defaults to `Towers`.
+		Towers []TowerType
+		// Enemies spawned per round, defaults to `Waves`.
+		Waves *WaveSet
+		// Spawn points generated, at least 1.
+		Spawns int
+		// Forks generated off the main road, branches merge back in or end in their own exit.
+		Forks int
+		// Valid modes: see `Modes`
+		Mode string
+		// Roads and obstacles to play on, generated when not set, sets the field size.
+		Map *Map
+		// Valid economies: see `Economies`
+		Economy string
 	}
 	GameState struct {
 		// Valid states: `waiting`, `started`, `paused`, `stopped`
@@ -36,19 +59,85 @@ type (
 		Roads     []*RoadObj
 		Towers    []*TowerObj
 		Enemies   []*EnemyObj
+		// Shots of towers with a projectile speed still in flight.
+		Projectiles []*ProjectileObj
+		// Amount of simulated ticks of `TickDuration`.
+		Tick  int
+		Stats GameStats
+		// Lanes of the field in versus, 1 for every player at the start.
+		Lanes int
+		// Last player with health left in versus, -1 when none.
+		Winner int
+	}
+	GameStats struct {
+		// Coins earned from defeated enemies.
+		CoinsEarned int
+		// Damage dealt per tower name.
+		Damage map[string]int
+		// Enemies that reached the end per round, starting at round 1.
+		Leaks []int
 	}
 	Player struct {
 		Index int
+		// Always 0 besides the first player in the shared economy.
 		Coins int
+		Stats PlayerStats
+		// Health and phase of the own lane, only used in versus.
+		Health int
+		Phase  string
+		// Coins received every round for enemies sent.
+		Income int
+		// Enemies sent by other players, spawned in the next round.
+		Queue []string
+	}
+	PlayerStats struct {
+		// Coins received from defeated enemies.
+		CoinsEarned int
+		// Coins spent on towers, upgrades and obstacles.
+		CoinsSpent int
+		// Damage dealt and enemies defeated by towers of the player.
+		Damage int
+		Kills  int
+		// Coins gifted to and received from other players.
+		Gifted   int
+		Received int
+		// Enemies sent to other players.
+		Sent int
 	}
+	// Game is safe for concurrent use, commands are applied between ticks.
+	//
+	// Fields and the Check/Get methods may only be read from within the Run callback or View.
 	Game struct {
 		GC      GameConfig
 		GS      GameState
 		Players []Player
 		exit    chan error
+		rng     *rand.Rand
+		rngSrc  *rand.PCG
+		uid     atomic.Int64
+		mu      sync.RWMutex
+		// Game time not yet simulated.
+		lag time.Duration
+		// Steps from the spawn of every tile reached by the last maze path search, nil when unknown.
+		mazeDepth map[[2]int]int
+		// Snapshot and commands since, written by `SaveReplay`.
+		recordStart saveFile
+		commands    []Command
+		// Replay being played back, nil when live.
+		replay      *replayFile
+		replayIndex int
+		// Sends commands to the hosting game instead of applying them, nil when local.
+		remote func(Command) error
 	}
 )
 
+const (
+	// Game time simulated per tick, independent of game speed and frame rate.
+	TickDuration = time.Millisecond * 20
+	// Ticks simulated per frame at most, the game slows down instead of falling behind.
+	maxFrameTicks = 1024
+)
+
 var (
 	Errors = gameErrors{
 		GameStateNotWaiting:  errors.New("game state is not waiting"),
@@ -59,83 +148,112 @@ var (
 		InvalidSelection:     errors.New("selection is invalid"),
 		InvalidPlayer:        errors.New("player is invalid"),
 		TowerNotExists:       errors.New("tower does not exists"),
+		WavesNotExists:       errors.New("wave set does not exists"),
+		UpgradeNotExists:     errors.New("upgrade does not exists"),
+		InvalidTargeting:     errors.New("targeting mode is invalid"),
+		InvalidMode:          errors.New("game mode is invalid"),
+		InvalidEconomy:       errors.New("economy is invalid"),
 		InsufficientFunds:    errors.New("not enough funds"),
+		InvalidGift:          errors.New("gift is invalid"),
+		PathBlocked:          errors.New("path is blocked"),
+		InvalidSave:          errors.New("save is invalid"),
+		InvalidReplay:        errors.New("replay is invalid"),
+		InvalidCommand:       errors.New("command is invalid"),
+		ReplayReadOnly:       errors.New("game is a replay"),
+		NotReplay:            errors.New("game is not a replay"),
+		RemoteGame:           errors.New("game is hosted remotely"),
+		NotRemote:            errors.New("game is not hosted remotely"),
+		NotEnoughPlayers:     errors.New("not enough players"),
+		FieldTooNarrow:       errors.New("field is too narrow"),
 		Exit:                 errors.New("game is exiting"),
 	}
 
-	Towers = []TowerObj{
-		{
-			x: 0, y: 0, UID: -1,
-			Name:           "Soldier",
-			Cost:           25,
-			Range:          3,
-			Rotation:       0.0,
-			damage:         1,
-			reloadSpeed:    1.0,
-			ReloadProgress: 0.0,
-			effectiveRange: []*RoadObj{},
-		}, {
-			x: 0, y: 0, UID: -1,
-			Name:           "Sniper",
-			Cost:           50,
-			Range:          10,
-			Rotation:       0.0,
-			damage:         1,
-			reloadSpeed:    0.25,
-			ReloadProgress: 0.0,
-			effectiveRange: []*RoadObj{},
-		}, {
-			x: 0, y: 0, UID: -1,
-			Name:           "Scout",
-			Cost:           75,
-			Range:          2,
-			Rotation:       0.0,
-			damage:         1,
-			reloadSpeed:    1.5,
-			ReloadProgress: 0.0,
-			effectiveRange: []*RoadObj{},
-		}, {
-			x: 0, y: 0, UID: -1,
-			ReloadProgress: 0.0, Rotation: 0.0, effectiveRange: []*RoadObj{},
-			Name:        "Heavy",
-			Cost:        75,
-			Range:       2,
-			damage:      5,
-			reloadSpeed: 0.5,
-		},
-	}
-
-	uid = 0
+	// Valid targeting modes of towers, the first is used for new towers.
+	TargetingModes = []string{"first", "last", "strongest", "weakest", "fastest", "closest"}
 )
 
 func NewGame(gc GameConfig) *Game {
-	return &Game{
-		GC: gc,
-		GS: GameState{
-			State:     "waiting",
-			Phase:     "building",
-			Round:     0,
-			Health:    100,
-			Obstacles: []*ObstacleObj{},
-			Roads:     []*RoadObj{},
-			Towers:    []*TowerObj{},
-			Enemies:   []*EnemyObj{},
-		},
-		Players: []Player{},
-		exit:    make(chan error),
+	if gc.Seed == 0 {
+		gc.Seed = uint64(rand.Int64())
+	}
+	if gc.Towers == nil {
+		gc.Towers = Towers
+	}
+	if gc.Waves == nil {
+		gc.Waves = Waves
+	}
+	if gc.Spawns < 1 {
+		gc.Spawns = 1
+	}
+	if gc.Mode == "" {
+		gc.Mode = Modes[0]
+	}
+	if gc.Economy == "" {
+		gc.Economy = Economies[0]
+	}
+	if gc.Map != nil {
+		gc.FieldWidth, gc.FieldHeight = gc.Map.Width, gc.Map.Height
 	}
+	game := &Game{
+		GC:   gc,
+		exit: make(chan error),
+	}
+	game.reset()
+	return game
+}
+
+func (game *Game) reset() {
+	game.GS = GameState{
+		State:       "waiting",
+		Phase:       "building",
+		Round:       0,
+		Health:      100,
+		Obstacles:   []*ObstacleObj{},
+		Roads:       []*RoadObj{},
+		Towers:      []*TowerObj{},
+		Enemies:     []*EnemyObj{},
+		Projectiles: []*ProjectileObj{},
+		Stats:       GameStats{Damage: map[string]int{}, Leaks: []int{}},
+		Winner:      -1,
+	}
+	game.Players = []Player{}
+	game.rngSrc = rand.NewPCG(game.GC.Seed, game.GC.Seed)
+	game.rng = rand.New(game.rngSrc)
+	game.uid.Store(0)
+	game.lag = 0
+	game.startRecording()
 }
 
 func (game *Game) Run(callback func(time.Duration) error) error {
 	processTime := time.Duration(0)
 	last := time.Now()
-	for game.GS.State != "stopped" {
+	for !game.Stopped() {
 		now := time.Now()
 
-		if game.GC.GameSpeed > 0 {
-			game.iterate(time.Since(last) * time.Duration(1<<(game.GC.GameSpeed-1)))
+		game.mu.Lock()
+		if game.GC.GameSpeed > 0 && game.GS.State == "started" {
+			game.lag += now.Sub(last) * time.Duration(1<<(game.GC.GameSpeed-1))
+			for ticks := 0; game.lag >= TickDuration; ticks++ {
+				if ticks >= maxFrameTicks {
+					game.lag = 0
+					break
+				}
+				game.step()
+				game.lag -= TickDuration
+			}
+		}
+		game.mu.Unlock()
+
+		// Renderers read the state while holding the read lock, commands wait until drawing is done.
+		game.mu.RLock()
+		var err error
+		if game.GS.State != "stopped" {
+			err = callback(processTime)
 		}
-		if err := callback(processTime); err != nil {
+		// Read while locked, restoring a game replaces the config.
+		tickDelay := game.GC.TickDelay
+		game.mu.RUnlock()
+		if err != nil {
 			if err == Errors.Exit {
 				return nil
 			}
@@ -144,24 +262,98 @@ func (game *Game) Run(callback func(time.Duration) error) error {
 
 		last = now
 		processTime = time.Since(now)
-		time.Sleep((game.GC.TickDelay / time.Duration(1<<max(0, game.GC.GameSpeed-1))) - time.Since(now))
+		time.Sleep(tickDelay - time.Since(now))
 	}
 	return nil
 }
 
+// Progress towards the next tick in range [0, 1), used by renderers to interpolate between ticks.
+func (game *Game) TickProgress() float64 {
+	return float64(game.lag) / float64(TickDuration)
+}
+
+// Run fn with a consistent read only view of the game.
+func (game *Game) View(fn func()) {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+	fn()
+}
+
+// Simulate ticks as fast as possible, for running without a renderer.
+func (game *Game) Step(ticks int) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	for range ticks {
+		if game.GS.State != "started" {
+			return
+		}
+		game.step()
+	}
+}
+
+func (game *Game) Stopped() bool {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+	return game.GS.State == "stopped"
+}
+
+func (game *Game) newUID() int {
+	return int(game.uid.Add(1))
+}
+
 func (game *Game) Start() error {
+	return game.command(Command{Kind: "start"})
+}
+
+func (game *Game) start() error {
 	if game.GS.State != "waiting" {
 		return Errors.GameStateNotWaiting
 	}
+	if !slices.Contains(Modes, game.GC.Mode) {
+		return Errors.InvalidMode
+	}
+	if !slices.Contains(Economies, game.GC.Economy) {
+		return Errors.InvalidEconomy
+	}
+	if game.GC.Mode == "versus" {
+		// Rewards only go to the defender of the lane.
+		if game.GC.Economy != "owner" {
+			return Errors.InvalidEconomy
+		} else if game.GC.Map != nil {
+			return Errors.InvalidMode
+		} else if len(game.Players) < 2 {
+			return Errors.NotEnoughPlayers
+		} else if game.GC.FieldWidth/len(game.Players) < 3 {
+			return Errors.FieldTooNarrow
+		}
+	}
+	if game.GC.Map != nil {
+		if err := game.GC.Map.validate(); err != nil {
+			return err
+		}
+	}
 
-	game.genRoads()
-	game.genObstacles()
+	if game.GC.Map != nil {
+		game.genMap()
+	} else if game.GC.Mode == "maze" {
+		game.genMaze()
+		game.genObstacles()
+	} else if game.GC.Mode == "versus" {
+		game.genLanes()
+	} else {
+		game.genRoads()
+		game.genObstacles()
+	}
 
 	game.GS.State = "started"
 	return nil
 }
 
 func (game *Game) Stop() error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
 	if game.GS.State != "started" && game.GS.State != "paused" {
 		return Errors.GameStateNotActive
 	}
@@ -171,15 +363,41 @@ func (game *Game) Stop() error {
 }
 
 func (game *Game) AddPlayer() int {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if err := game.record(Command{Kind: "player"}); err != nil {
+		return -1
+	}
+	return len(game.Players) - 1
+}
+
+func (game *Game) addPlayer() int {
 	index := len(game.Players)
 	game.Players = append(game.Players, Player{
-		Index: index,
-		Coins: 80,
+		Index:  index,
+		Coins:  80,
+		Health: 100,
+		Phase:  "building",
+		Queue:  []string{},
 	})
+	// Lanes are divided at the start, players joining later only watch.
+	if game.GC.Mode == "versus" && game.GS.State != "waiting" {
+		game.Players[index].Phase = "lost"
+	}
+	// Every player adds their starting coins to the shared wallet.
+	if game.GC.Economy == "shared" && index > 0 {
+		game.Players[0].Coins += game.Players[index].Coins
+		game.Players[index].Coins = 0
+	}
 	return index
 }
 
 func (game *Game) TogglePause() {
+	_ = game.command(Command{Kind: "pause"})
+}
+
+func (game *Game) togglePause() {
 	switch game.GS.State {
 	case "started":
 		game.GS.State = "paused"
@@ -188,57 +406,98 @@ func (game *Game) TogglePause() {
 	}
 }
 
+func (game *Game) SetGameSpeed(speed int) {
+	_ = game.command(Command{Kind: "speed", Value: speed})
+}
+
+func (game *Game) setGameSpeed(speed int) {
+	game.GC.GameSpeed = max(0, min(speed, 9))
+}
+
 func (game *Game) StartRound() error {
+	return game.command(Command{Kind: "round"})
+}
+
+func (game *Game) startRound() error {
 	if game.GS.State != "started" && game.GS.State != "paused" {
 		return Errors.GameStateNotActive
 	} else if game.GS.Phase != "building" {
 		return Errors.GamePhaseNotBuilding
 	}
 
+	groups, err := game.GC.Waves.groups(game.GS.Round+1, game.rng)
+	if err != nil {
+		return err
+	}
+
 	game.GS.Round += 1
-	game.spawnEnemies()
+	game.GS.Stats.Leaks = append(game.GS.Stats.Leaks, 0)
+	if game.GC.Mode == "versus" {
+		game.spawnLanes(groups)
+	} else {
+		game.spawnEnemies(groups, game.Spawns())
+	}
 
 	game.GS.Phase = "defending"
 	return nil
 }
 
 func (game *Game) PlaceTower(name string, x, y, pid int) error {
+	return game.command(Command{Kind: "place", Name: name, X: x, Y: y, PID: pid})
+}
+
+func (game *Game) placeTower(name string, x, y, pid int) error {
 	if game.GS.State != "started" && game.GS.State != "paused" {
 		return Errors.GameStateNotActive
 	}
 	if pid < 0 || pid >= len(game.Players) {
 		return Errors.InvalidPlayer
 	}
-	if game.CheckCollisions(x, y) {
+	if game.GC.Mode == "versus" && (!game.alive(pid) || game.laneOf(x) != pid) {
 		return Errors.InvalidPlacement
 	}
+	if err := game.checkPlacement(x, y); err != nil {
+		return err
+	}
 
-	i := slices.IndexFunc(Towers, func(obj TowerObj) bool { return obj.Name == name })
+	i := slices.IndexFunc(game.GC.Towers, func(obj TowerType) bool { return obj.Name == name })
 	if i < 0 {
 		return Errors.TowerNotExists
 	}
-	tower := Towers[i]
+	tower := TowerObj{TowerType: game.GC.Towers[i], Targeting: TargetingModes[0], effectiveRange: []*RoadObj{}}
 
-	if tower.Cost > game.Players[pid].Coins {
+	if tower.Cost > *game.wallet(pid) {
 		return Errors.InsufficientFunds
 	}
-	game.Players[pid].Coins -= tower.Cost
+	game.spend(pid, tower.Cost)
 
-	uid += 1
-	tower.x, tower.y, tower.UID, tower.Owner = x, y, uid, pid
-	for offsetY := range (tower.Range * 2) + 1 {
-		for offsetX := range (tower.Range * 2) + 1 {
-			tower.effectiveRange = append(tower.effectiveRange, game.GetCollisionRoads(x+(offsetX-tower.Range), y+(offsetY-tower.Range))...)
-		}
-	}
-	slices.SortFunc(tower.effectiveRange, func(a, b *RoadObj) int { return b.Index - a.Index })
+	tower.x, tower.y, tower.UID, tower.Owner, tower.Spent = x, y, game.newUID(), pid, tower.Cost
+	game.updateEffectiveRange(&tower)
 
 	game.GS.Towers = append(game.GS.Towers, &tower)
+	game.updateMazeTile(x, y)
 
 	return nil
 }
 
+func (game *Game) updateEffectiveRange(tower *TowerObj) {
+	tower.effectiveRange = []*RoadObj{}
+	for offsetY := range (tower.Range * 2) + 1 {
+		for offsetX := range (tower.Range * 2) + 1 {
+			tower.effectiveRange = append(tower.effectiveRange, game.GetCollisionRoads(tower.x+(offsetX-tower.Range), tower.y+(offsetY-tower.Range))...)
+		}
+	}
+	if game.GC.Mode == "versus" {
+		tower.effectiveRange = slices.DeleteFunc(tower.effectiveRange, func(obj *RoadObj) bool { return game.laneOf(obj.x) != game.laneOf(tower.x) })
+	}
+	slices.SortFunc(tower.effectiveRange, func(a, b *RoadObj) int { return a.Distance - b.Distance })
+}
+
 func (game *Game) DestroyTower(x, y, pid int) error {
+	return game.command(Command{Kind: "destroy", X: x, Y: y, PID: pid})
+}
+
+func (game *Game) destroyTower(x, y, pid int) error {
 	if game.GS.State != "started" && game.GS.State != "paused" {
 		return Errors.GameStateNotActive
 	}
@@ -253,13 +512,85 @@ func (game *Game) DestroyTower(x, y, pid int) error {
 		return Errors.InvalidPlayer
 	}
 
-	game.Players[pid].Coins += int(float64(towers[0].Cost) * game.GC.RefundMultiplier)
+	*game.wallet(pid) += int(float64(towers[0].Spent) * game.GC.RefundMultiplier)
 	game.GS.Towers = slices.DeleteFunc(game.GS.Towers, func(obj *TowerObj) bool { return obj.UID == towers[0].UID })
+	game.updateMazeTile(x, y)
+
+	return nil
+}
+
+func (game *Game) UpgradeTower(x, y, pid, path int) error {
+	return game.command(Command{Kind: "upgrade", X: x, Y: y, PID: pid, Value: path})
+}
+
+func (game *Game) upgradeTower(x, y, pid, path int) error {
+	if game.GS.State != "started" && game.GS.State != "paused" {
+		return Errors.GameStateNotActive
+	}
+	if pid < 0 || pid >= len(game.Players) {
+		return Errors.InvalidPlayer
+	}
+	towers := game.GetCollisionTowers(x, y)
+	if len(towers) != 1 {
+		return Errors.InvalidSelection
+	}
+	tower := towers[0]
+	if tower.Owner != pid {
+		return Errors.InvalidPlayer
+	}
+
+	upgrade, ok := tower.NextUpgrade(path)
+	if !ok {
+		return Errors.UpgradeNotExists
+	}
+	if upgrade.Cost > *game.wallet(pid) {
+		return Errors.InsufficientFunds
+	}
+	game.spend(pid, upgrade.Cost)
+
+	tower.Path, tower.Tier, tower.Spent = path, tower.Tier+1, tower.Spent+upgrade.Cost
+	tower.Damage += upgrade.Damage
+	tower.ReloadSpeed += upgrade.ReloadSpeed
+	tower.Splash += upgrade.Splash
+	if upgrade.Range != 0 {
+		tower.Range += upgrade.Range
+		game.updateEffectiveRange(tower)
+	}
+
+	return nil
+}
+
+func (game *Game) SetTargeting(x, y, pid int, mode string) error {
+	return game.command(Command{Kind: "target", Name: mode, X: x, Y: y, PID: pid})
+}
+
+func (game *Game) setTargeting(x, y, pid int, mode string) error {
+	if game.GS.State != "started" && game.GS.State != "paused" {
+		return Errors.GameStateNotActive
+	}
+	if pid < 0 || pid >= len(game.Players) {
+		return Errors.InvalidPlayer
+	}
+	if !slices.Contains(TargetingModes, mode) {
+		return Errors.InvalidTargeting
+	}
+	towers := game.GetCollisionTowers(x, y)
+	if len(towers) != 1 {
+		return Errors.InvalidSelection
+	}
+	if towers[0].Owner != pid {
+		return Errors.InvalidPlayer
+	}
 
+	towers[0].Targeting = mode
 	return nil
 }
 
 func (game *Game) DestroyObstacle(x, y, pid int) error {
+	return game.command(Command{Kind: "obstacle", X: x, Y: y, PID: pid})
+}
+
+func (game *Game) destroyObstacle(x, y, pid int) error {
 	if game.GS.State != "started" && game.GS.State != "paused" {
 		return Errors.GameStateNotActive
 	}
@@ -272,23 +603,24 @@ func (game *Game) DestroyObstacle(x, y, pid int) error {
 	}
 	obstacle := obstacles[0]
 
-	if obstacle.Cost > game.Players[pid].Coins {
+	if obstacle.Cost > *game.wallet(pid) {
 		return Errors.InsufficientFunds
 	}
-	game.Players[pid].Coins -= obstacle.Cost
+	game.spend(pid, obstacle.Cost)
 
 	game.GS.Obstacles = slices.DeleteFunc(game.GS.Obstacles, func(obj *ObstacleObj) bool { return obj.UID == obstacle.UID })
+	game.updateMazeTile(x, y)
 
 	return nil
 }
 
 func (game *Game) genRoads() {
-	x, y, dir := rand.IntN(game.GC.FieldWidth), rand.IntN(game.GC.FieldHeight), [4]string{"up", "right", "down", "left"}[rand.IntN(4)]
+	x, y, dir := game.rng.IntN(game.GC.FieldWidth), game.rng.IntN(game.GC.FieldHeight), directions[game.rng.IntN(4)]
 	index, conRetries := 0, 0
-	for i := 0; i < int(float64(game.GC.FieldWidth+game.GC.FieldHeight)*(2+rand.Float64())); i++ {
+	for i := 0; i < int(float64(game.GC.FieldWidth+game.GC.FieldHeight)*(2+game.rng.Float64())); i++ {
 		oldX, oldY, oldDir := x, y, dir
 
-		switch n := rand.IntN(8); {
+		switch n := game.rng.IntN(8); {
 		case n == 0 && oldDir != "down":
 			dir = "up"
 		case n == 1 && oldDir != "left":
@@ -300,40 +632,8 @@ func (game *Game) genRoads() {
 		default:
 		}
 
-		switch dir {
-		case "up":
-			y -= 1
-			if y < 0 {
-				y = game.GC.FieldHeight - 1
-			}
-		case "right":
-			x += 1
-			if x >= game.GC.FieldWidth {
-				x = 0
-			}
-		case "down":
-			y += 1
-			if y >= game.GC.FieldHeight {
-				y = 0
-			}
-		case "left":
-			x -= 1
-			if x < 0 {
-				x = game.GC.FieldWidth - 1
-			}
-		}
-
-		dirEntrance := ""
-		switch oldDir {
-		case "up":
-			dirEntrance = "down"
-		case "right":
-			dirEntrance = "left"
-		case "down":
-			dirEntrance = "up"
-		case "left":
-			dirEntrance = "right"
-		}
+		x, y = Neighbour(x, y, dir, game.GC.FieldWidth, game.GC.FieldHeight)
+		dirEntrance := Opposite(oldDir)
 
 		if (conRetries < 2 && game.CheckCollisions(x, y)) || (game.CheckCollisionObstacles(x, y) || game.CheckCollisionTowers(x, y)) {
 			x, y, dir = oldX, oldY, oldDir
@@ -349,25 +649,28 @@ func (game *Game) genRoads() {
 			x: oldX, y: oldY,
 			Index:       index,
 			DirEntrance: dirEntrance, DirExit: dir,
+			Next: []int{index + 1}, Weight: 1,
 		})
 		index += 1
 	}
 	game.GS.Roads[0].DirEntrance = "start"
 	game.GS.Roads[len(game.GS.Roads)-1].DirExit = "end"
+	game.GS.Roads[len(game.GS.Roads)-1].Next = []int{}
+
+	game.genBranches()
 }
 
 func (game *Game) genObstacles() {
-	for range int(float64(game.GC.FieldWidth+game.GC.FieldHeight) * rand.Float64()) {
-		x, y := rand.IntN(game.GC.FieldWidth), rand.IntN(game.GC.FieldHeight)
+	for range int(float64(game.GC.FieldWidth+game.GC.FieldHeight) * game.rng.Float64()) {
+		x, y := game.rng.IntN(game.GC.FieldWidth), game.rng.IntN(game.GC.FieldHeight)
 
 		if game.CheckCollisions(x, y) {
 			continue
 		}
 
-		uid += 1
 		game.GS.Obstacles = append(game.GS.Obstacles, &ObstacleObj{
 			x: x, y: y,
-			UID:  uid,
+			UID:  game.newUID(),
 			Cost: 100,
 		})
 	}
@@ -377,14 +680,19 @@ func (game *Game) iterate(delta time.Duration) {
 	if game.GS.State == "paused" {
 		return
 	}
+	game.GS.Tick += 1
 
 	if game.GS.Phase == "building" {
 		for _, tower := range game.GS.Towers {
+			if tower.Disabled > 0 {
+				tower.Disabled = max(0, tower.Disabled-int(delta.Milliseconds()))
+				continue
+			}
 			if tower.ReloadProgress < 1 {
-				tower.ReloadProgress += (float64(delta.Milliseconds()) / 1000) * tower.reloadSpeed
+				tower.ReloadProgress += delta.Seconds() * tower.ReloadSpeed
 			}
 
-			if r := float64(rand.IntN(5000)); r <= 90 {
+			if r := float64(game.rng.IntN(5000)); r <= 90 {
 				tower.Rotation += r - 45
 				if tower.Rotation < 0 {
 					tower.Rotation += 360
@@ -395,36 +703,35 @@ func (game *Game) iterate(delta time.Duration) {
 		}
 	} else if game.GS.Phase == "defending" {
 		for _, tower := range game.GS.Towers {
+			if tower.Disabled > 0 {
+				tower.Disabled = max(0, tower.Disabled-int(delta.Milliseconds()))
+				continue
+			}
 			if tower.ReloadProgress < 1 {
-				tower.ReloadProgress += (float64(delta.Milliseconds()) / 1000) * tower.reloadSpeed
+				tower.ReloadProgress += delta.Seconds() * tower.ReloadSpeed
 			}
 			if tower.ReloadProgress < 1 {
 				continue
 			}
 
-			for _, road := range tower.effectiveRange {
-				enemies := game.GetCollisionEnemies(road.x, road.y)
-				i := slices.IndexFunc(enemies, func(obj *EnemyObj) bool { return obj.startDelay <= 0 })
-				if i < 0 {
-					continue
-				}
-				enemies[i].Health -= min(enemies[i].Health, tower.damage)
-				tower.ReloadProgress -= 1
-				tower.Rotation = (math.Atan2(float64(enemies[i].y-tower.y), float64(enemies[i].x-tower.x)) * (180 / math.Pi)) + 90
-				if tower.Rotation < 0 {
-					tower.Rotation += 360
-				}
-
-				if enemies[i].Health <= 0 {
-					game.Players[max(len(game.Players)-1, tower.Owner)].Coins += enemies[i].reward
-					game.GS.Enemies = slices.DeleteFunc(game.GS.Enemies, func(obj *EnemyObj) bool { return obj.UID == enemies[i].UID })
-				}
-				break
+			enemy := game.target(tower)
+			if enemy == nil {
+				continue
+			}
+			game.fire(tower, enemy)
+			tower.ReloadProgress -= 1
+			tower.Rotation = (math.Atan2(float64(enemy.y-tower.y), float64(enemy.x-tower.x)) * (180 / math.Pi)) + 90
+			if tower.Rotation < 0 {
+				tower.Rotation += 360
 			}
 		}
+		game.moveProjectiles(delta)
+		game.tickEffects(delta)
+		game.tickAbilities(delta)
 
 		toPop := []int{}
 		for i, enemy := range game.GS.Enemies {
+			enemy.lastProgress = enemy.Progress
 			if enemy.startDelay > 0 {
 				enemy.startDelay -= int(delta.Milliseconds())
 				if enemy.startDelay < 0 {
@@ -434,14 +741,20 @@ func (game *Game) iterate(delta time.Duration) {
 				continue
 			}
 
-			enemy.Progress += (float64(delta.Milliseconds()) / 1000) * enemy.speedMultiplier
+			enemy.Progress += delta.Seconds() * enemy.speedMultiplier * enemy.speedFactor()
 
-			if int(enemy.Progress) >= len(game.GS.Roads) {
-				game.GS.Health = max(game.GS.Health-enemy.Health, 0)
+			if int(enemy.Progress) >= len(enemy.Route) {
+				if game.GC.Mode == "versus" {
+					player := &game.Players[game.laneOf(enemy.x)]
+					player.Health = max(player.Health-enemy.Health, 0)
+				} else {
+					game.GS.Health = max(game.GS.Health-enemy.Health, 0)
+				}
+				game.GS.Stats.Leaks[game.GS.Round-1] += 1
 				toPop = append(toPop, i)
 				continue
 			}
-			enemy.x, enemy.y = game.GS.Roads[int(enemy.Progress)].Cord()
+			enemy.x, enemy.y = game.GS.Roads[enemy.Route[int(enemy.Progress)]].Cord()
 		}
 		slices.Reverse(toPop)
 		for _, i := range toPop {
@@ -450,6 +763,17 @@ func (game *Game) iterate(delta time.Duration) {
 
 		if len(game.GS.Enemies) <= 0 {
 			game.GS.Phase = "building"
+			game.GS.Projectiles = []*ProjectileObj{}
+			for _, tower := range game.GS.Towers {
+				tower.Disabled = 0
+			}
+			game.updateMaze()
+		}
+		if game.GC.Mode == "versus" {
+			game.updateLanes()
+			if game.GS.Phase == "lost" {
+				return
+			}
 		}
 		if game.GS.Health <= 0 || len(game.Players) <= 0 {
 			game.GS.Round = max(game.GS.Round-1, 0)
@@ -458,3 +782,39 @@ func (game *Game) iterate(delta time.Duration) {
 		}
 	}
 }
+
+// Spawned enemy in range preferred by the towers targeting mode, nil if none.
+func (game *Game) target(tower *TowerObj) *EnemyObj {
+	var target *EnemyObj
+	for _, road := range tower.effectiveRange {
+		for _, enemy := range game.GetCollisionEnemies(road.x, road.y) {
+			if enemy.startDelay > 0 || enemy == target || (enemy.EnemyType().Stealth && !tower.Detection) {
+				continue
+			}
+			if target == nil || tower.prefers(enemy, target) {
+				target = enemy
+			}
+		}
+	}
+	return target
+}
+
+// Ties keep the current target.
+func (tower *TowerObj) prefers(enemy, target *EnemyObj) bool {
+	switch tower.Targeting {
+	case "last":
+		return enemy.Remaining() > target.Remaining()
+	case "strongest":
+		return enemy.Health > target.Health
+	case "weakest":
+		return enemy.Health < target.Health
+	case "fastest":
+		return enemy.speedMultiplier > target.speedMultiplier
+	case "closest":
+		distance := func(obj *EnemyObj) int {
+			return ((obj.x - tower.x) * (obj.x - tower.x)) + ((obj.y - tower.y) * (obj.y - tower.y))
+		}
+		return distance(enemy) < distance(target)
+	}
+	return enemy.Remaining() < target.Remaining()
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Started game of seed in mode with a single player.
+func newTestGame(t *testing.T, mode string, seed uint64) (*Game, int) {
+	t.Helper()
+
+	gm := NewGame(GameConfig{FieldWidth: 20, FieldHeight: 15, GameSpeed: 1, RefundMultiplier: 0.5, TickDelay: time.Millisecond, Seed: seed, Mode: mode})
+	if err := gm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return gm, gm.AddPlayer()
+}
+
+// Place towers and play rounds, the same game gets the same inputs every time.
+func play(gm *Game, pid, rounds int) {
+	for r := range rounds {
+		for x := 0; x < 20; x += 3 {
+			// Invalid placements are refused the same way every time.
+			_ = gm.PlaceTower(Towers[0].Name, x, (x*7+r)%15, pid)
+			gm.Step(5)
+		}
+		_ = gm.StartRound()
+		gm.Step(1500)
+	}
+}
+
+func saved(t *testing.T, gm *Game) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	if err := gm.Save(buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSeedDeterministic(t *testing.T) {
+	for _, mode := range []string{"roads", "maze"} {
+		a, pidA := newTestGame(t, mode, 7)
+		b, pidB := newTestGame(t, mode, 7)
+		c, pidC := newTestGame(t, mode, 8)
+		play(a, pidA, 4)
+		play(b, pidB, 4)
+		play(c, pidC, 4)
+
+		if !bytes.Equal(saved(t, a), saved(t, b)) {
+			t.Errorf("%v: games of the same seed differ", mode)
+		}
+		if bytes.Equal(saved(t, a), saved(t, c)) {
+			t.Errorf("%v: games of different seeds are the same", mode)
+		}
+	}
+}
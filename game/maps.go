@@ -0,0 +1,241 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+)
+
+type (
+	Map struct {
+		// Name of the map.
+		Name string `json:"name"`
+		// Field size in tiles.
+		Width  int `json:"width"`
+		Height int `json:"height"`
+		// Roads enemies follow, referenced by index.
+		Roads []MapRoad `json:"roads"`
+		// Obstacles blocking tower placement until removed.
+		Obstacles []MapObstacle `json:"obstacles,omitempty"`
+	}
+	MapRoad struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		// Valid directions: `up`, `right`, `down`, `left`, entrance `start` for spawns and exit `end` for exits.
+		Entrance string `json:"entrance"`
+		Exit     string `json:"exit"`
+		// Indexes of the roads enemies continue on, the road the exit points at when not set.
+		Next []int `json:"next,omitempty"`
+		// Chance of being picked at a fork relative to the other branches, 1 when not set.
+		Weight float64 `json:"weight,omitempty"`
+	}
+	MapObstacle struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		// Cost to remove.
+		Cost int `json:"cost"`
+	}
+)
+
+// Read and validate a map, errors point at the offending road or obstacle.
+func LoadMap(r io.Reader) (*Map, error) {
+	m := &Map{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(m); err != nil {
+		return nil, errors.New("map: " + err.Error())
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Map) error(path, msg string) error {
+	return errors.New("map " + strconv.Quote(m.Name) + ": " + path + ": " + msg)
+}
+
+func (m *Map) inBounds(x, y int) bool {
+	return x >= 0 && x < m.Width && y >= 0 && y < m.Height
+}
+
+// Check the map by the rules of `LoadMap` without filling in defaults, used by editors.
+func (m *Map) Validate() error {
+	clone := *m
+	clone.Roads = slices.Clone(m.Roads)
+	for i := range clone.Roads {
+		clone.Roads[i].Next = slices.Clone(clone.Roads[i].Next)
+	}
+	return clone.validate()
+}
+
+// Write the map in the format read by `LoadMap`.
+func (m *Map) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// Validate the map and fill in `MapRoad.Next` and `MapRoad.Weight` when not set.
+func (m *Map) validate() error {
+	if m.Width < 1 {
+		return m.error("width", "is less than 1")
+	} else if m.Height < 1 {
+		return m.error("height", "is less than 1")
+	} else if len(m.Roads) == 0 {
+		return m.error("roads", "no roads defined")
+	}
+
+	for i := range m.Roads {
+		road, path := &m.Roads[i], "roads["+strconv.Itoa(i)+"]"
+		switch {
+		case !m.inBounds(road.X, road.Y):
+			return m.error(path, "position "+strconv.Itoa(road.X)+", "+strconv.Itoa(road.Y)+" is out of bounds")
+		case road.Entrance != "start" && !slices.Contains(directions[:], road.Entrance):
+			return m.error(path, "entrance "+strconv.Quote(road.Entrance)+" is unknown")
+		case road.Exit != "end" && !slices.Contains(directions[:], road.Exit):
+			return m.error(path, "exit "+strconv.Quote(road.Exit)+" is unknown")
+		case road.Entrance == road.Exit:
+			return m.error(path, "entrance and exit are both "+strconv.Quote(road.Exit))
+		case road.Weight < 0:
+			return m.error(path, "weight is negative")
+		case road.Exit == "end" && len(road.Next) > 0:
+			return m.error(path, "next is set on an exit")
+		}
+		if road.Weight == 0 {
+			road.Weight = 1
+		}
+
+		if road.Exit != "end" && len(road.Next) == 0 {
+			x, y := Neighbour(road.X, road.Y, road.Exit, m.Width, m.Height)
+			next := slices.IndexFunc(m.Roads, func(obj MapRoad) bool { return obj.X == x && obj.Y == y })
+			if next < 0 {
+				return m.error(path, "exit "+strconv.Quote(road.Exit)+" does not lead to a road")
+			}
+			// Prefer the road entered from this one when roads cross.
+			if j := slices.IndexFunc(m.Roads, func(obj MapRoad) bool {
+				fromX, fromY := Neighbour(obj.X, obj.Y, obj.Entrance, m.Width, m.Height)
+				return obj.X == x && obj.Y == y && fromX == road.X && fromY == road.Y
+			}); j >= 0 {
+				next = j
+			}
+			road.Next = []int{next}
+		}
+
+		for j, next := range road.Next {
+			if next < 0 || next >= len(m.Roads) {
+				return m.error(path+".next["+strconv.Itoa(j)+"]", "road "+strconv.Itoa(next)+" does not exist")
+			}
+		}
+	}
+
+	incoming := make([]int, len(m.Roads))
+	for _, road := range m.Roads {
+		for _, next := range road.Next {
+			incoming[next]++
+		}
+	}
+
+	for i, road := range m.Roads {
+		exitX, exitY := Neighbour(road.X, road.Y, road.Exit, m.Width, m.Height)
+		for j, next := range road.Next {
+			nextPath, obj := "roads["+strconv.Itoa(i)+"].next["+strconv.Itoa(j)+"]", m.Roads[next]
+			if obj.X != exitX || obj.Y != exitY {
+				return m.error(nextPath, "road "+strconv.Itoa(next)+" is not at the exit of this road")
+			}
+			// Merges are entered from one of their roads only.
+			if incoming[next] == 1 && obj.Entrance != Opposite(road.Exit) {
+				return m.error(nextPath, "road "+strconv.Itoa(next)+" is not entered from this road")
+			}
+		}
+	}
+	spawns := []int{}
+	for i, road := range m.Roads {
+		path := "roads[" + strconv.Itoa(i) + "]"
+		if road.Entrance == "start" && incoming[i] > 0 {
+			return m.error(path, "spawn is entered from another road")
+		} else if road.Entrance != "start" && incoming[i] == 0 {
+			return m.error(path, "entrance "+strconv.Quote(road.Entrance)+" is not entered from a road")
+		}
+		if road.Entrance == "start" {
+			spawns = append(spawns, i)
+		}
+	}
+	if len(spawns) == 0 {
+		return m.error("roads", "no spawn defined")
+	} else if !slices.ContainsFunc(m.Roads, func(obj MapRoad) bool { return obj.Exit == "end" }) {
+		return m.error("roads", "no exit defined")
+	}
+
+	// Removing roads without incoming roads leaves only loops behind.
+	for queue := spawns; len(queue) > 0; queue = queue[1:] {
+		for _, next := range m.Roads[queue[0]].Next {
+			if incoming[next]--; incoming[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if i := slices.IndexFunc(incoming, func(n int) bool { return n > 0 }); i >= 0 {
+		return m.error("roads["+strconv.Itoa(i)+"]", "is part of a loop")
+	}
+
+	for i, obstacle := range m.Obstacles {
+		path := "obstacles[" + strconv.Itoa(i) + "]"
+		switch {
+		case !m.inBounds(obstacle.X, obstacle.Y):
+			return m.error(path, "position "+strconv.Itoa(obstacle.X)+", "+strconv.Itoa(obstacle.Y)+" is out of bounds")
+		case obstacle.Cost < 0:
+			return m.error(path, "cost is negative")
+		case slices.ContainsFunc(m.Roads, func(obj MapRoad) bool { return obj.X == obstacle.X && obj.Y == obstacle.Y }):
+			return m.error(path, "position "+strconv.Itoa(obstacle.X)+", "+strconv.Itoa(obstacle.Y)+" is on a road")
+		case slices.ContainsFunc(m.Obstacles[:i], func(obj MapObstacle) bool { return obj.X == obstacle.X && obj.Y == obstacle.Y }):
+			return m.error(path, "position "+strconv.Itoa(obstacle.X)+", "+strconv.Itoa(obstacle.Y)+" has another obstacle")
+		}
+	}
+
+	return nil
+}
+
+// Write the current roads and obstacles as a map that can be read with `LoadMap`.
+func (game *Game) ExportMap(w io.Writer) error {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	m := Map{Width: game.GC.FieldWidth, Height: game.GC.FieldHeight, Roads: []MapRoad{}, Obstacles: []MapObstacle{}}
+	if game.GC.Map != nil {
+		m.Name = game.GC.Map.Name
+	}
+	for _, obj := range game.GS.Roads {
+		m.Roads = append(m.Roads, MapRoad{X: obj.x, Y: obj.y, Entrance: obj.DirEntrance, Exit: obj.DirExit, Next: slices.Clone(obj.Next), Weight: obj.Weight})
+	}
+	for _, obj := range game.GS.Obstacles {
+		m.Obstacles = append(m.Obstacles, MapObstacle{X: obj.x, Y: obj.y, Cost: obj.Cost})
+	}
+
+	return m.Save(w)
+}
+
+// Place the roads and obstacles of `GameConfig.Map`, maze mode only keeps the first spawn and exit.
+func (game *Game) genMap() {
+	for i, road := range game.GC.Map.Roads {
+		game.GS.Roads = append(game.GS.Roads, &RoadObj{
+			x: road.X, y: road.Y, Index: i,
+			DirEntrance: road.Entrance, DirExit: road.Exit,
+			Next: slices.Clone(road.Next), Weight: road.Weight,
+		})
+	}
+	for _, obstacle := range game.GC.Map.Obstacles {
+		game.GS.Obstacles = append(game.GS.Obstacles, &ObstacleObj{x: obstacle.X, y: obstacle.Y, UID: game.newUID(), Cost: obstacle.Cost})
+	}
+
+	if game.GC.Mode == "maze" {
+		spawn := game.Spawns()[0]
+		exit := game.GS.Roads[slices.IndexFunc(game.GS.Roads, func(obj *RoadObj) bool { return obj.DirExit == "end" })]
+		game.GS.Roads = []*RoadObj{{x: spawn.x, y: spawn.y, DirEntrance: "start"}, {x: exit.x, y: exit.y, DirExit: "end"}}
+		game.updateMaze()
+		return
+	}
+	game.updateRoadDistances()
+}
@@ -0,0 +1,133 @@
+package game
+
+import "slices"
+
+// Valid game modes, the first is used when `GameConfig.Mode` is empty.
+//
+// Roads follows generated roads, maze walks the shortest path across the field shaped by towers and obstacles,
+// versus gives every player their own lane to defend and send enemies into the lanes of the others.
+var Modes = []string{"roads", "maze", "versus"}
+
+// Pick a spawn on the left and an exit on the right edge and connect them.
+func (game *Game) genMaze() {
+	game.GS.Roads = []*RoadObj{
+		{x: 0, y: game.rng.IntN(game.GC.FieldHeight), DirEntrance: "start"},
+		{x: game.GC.FieldWidth - 1, y: game.rng.IntN(game.GC.FieldHeight), DirExit: "end"},
+	}
+	game.updateMaze()
+}
+
+// Shortest path of tiles without obstacles or towers from spawn to exit, nil when blocked.
+//
+// Also returns the steps from the spawn of every tile reached before the exit.
+func (game *Game) findPath(blocked func(x, y int) bool) ([][2]int, map[[2]int]int) {
+	spawn, exit := game.GS.Roads[0], game.GS.Roads[len(game.GS.Roads)-1]
+	from := map[[2]int][2]int{{spawn.x, spawn.y}: {spawn.x, spawn.y}}
+	depth := map[[2]int]int{{spawn.x, spawn.y}: 0}
+	for queue := [][2]int{{spawn.x, spawn.y}}; len(queue) > 0; queue = queue[1:] {
+		tile := queue[0]
+		if tile == [2]int{exit.x, exit.y} {
+			path := [][2]int{tile}
+			for tile != [2]int{spawn.x, spawn.y} {
+				tile = from[tile]
+				path = append(path, tile)
+			}
+			slices.Reverse(path)
+			return path, depth
+		}
+
+		for _, offset := range [4][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			next := [2]int{tile[0] + offset[0], tile[1] + offset[1]}
+			if _, ok := from[next]; ok || next[0] < 0 || next[0] >= game.GC.FieldWidth || next[1] < 0 || next[1] >= game.GC.FieldHeight {
+				continue
+			}
+			if game.CheckCollisionObstacles(next[0], next[1]) || game.CheckCollisionTowers(next[0], next[1]) || blocked(next[0], next[1]) {
+				continue
+			}
+			from[next], depth[next] = tile, depth[tile]+1
+			queue = append(queue, next)
+		}
+	}
+	return nil, depth
+}
+
+// Update the path after the tile x, y was blocked or freed, searching again only when it can change the path.
+//
+// Blocking a tile off the path leaves it as it is, tiles searched later only get further from the spawn.
+// Freeing a tile only changes the path when the tile is reached before the exit, found by the steps of the last search.
+// Steps only grow by blocking tiles, steps of earlier searches never miss a freed tile that is closer.
+func (game *Game) updateMazeTile(x, y int) {
+	if game.GC.Mode != "maze" || game.GS.Phase != "building" {
+		return
+	}
+	if game.CheckCollisionObstacles(x, y) || game.CheckCollisionTowers(x, y) {
+		if game.CheckCollisionRoads(x, y) {
+			game.updateMaze()
+		}
+		return
+	}
+	if game.mazeDepth == nil {
+		game.updateMaze()
+		return
+	}
+	for _, offset := range [4][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+		// The exit is len(Roads)-1 steps away, tiles reached at the same step or later never lead to it.
+		if depth, ok := game.mazeDepth[[2]int{x + offset[0], y + offset[1]}]; ok && depth+1 < len(game.GS.Roads)-1 {
+			game.updateMaze()
+			return
+		}
+	}
+}
+
+// Replace the roads with the shortest path, only while building so enemies never walk a changed route.
+func (game *Game) updateMaze() {
+	if game.GC.Mode != "maze" || game.GS.Phase != "building" {
+		return
+	}
+	path, depth := game.findPath(func(x, y int) bool { return false })
+	if path == nil {
+		return
+	}
+	game.mazeDepth = depth
+
+	dirs := map[[2]int]string{{0, -1}: "up", {1, 0}: "right", {0, 1}: "down", {-1, 0}: "left"}
+	roads := []*RoadObj{}
+	for i, tile := range path {
+		road := &RoadObj{x: tile[0], y: tile[1], Index: i, DirEntrance: "start", DirExit: "end", Next: []int{}, Weight: 1}
+		if i > 0 {
+			road.DirEntrance = dirs[[2]int{path[i-1][0] - tile[0], path[i-1][1] - tile[1]}]
+		}
+		if i < len(path)-1 {
+			road.DirExit = dirs[[2]int{path[i+1][0] - tile[0], path[i+1][1] - tile[1]}]
+			road.Next = []int{i + 1}
+		}
+		roads = append(roads, road)
+	}
+	game.GS.Roads = roads
+	game.updateRoadDistances()
+
+	for _, tower := range game.GS.Towers {
+		game.updateEffectiveRange(tower)
+	}
+}
+
+// Placement on the maze path is allowed while building as long as a path remains.
+func (game *Game) checkPlacement(x, y int) error {
+	if game.GC.Mode != "maze" || game.GS.Phase != "building" || !game.CheckCollisionRoads(x, y) {
+		if game.CheckCollisions(x, y) {
+			return Errors.InvalidPlacement
+		}
+		return nil
+	}
+
+	if road := game.GetCollisionRoads(x, y)[0]; road.DirEntrance == "start" || road.DirExit == "end" {
+		return Errors.InvalidPlacement
+	}
+	if game.CheckCollisionObstacles(x, y) || game.CheckCollisionTowers(x, y) || game.CheckCollisionEnemies(x, y) {
+		return Errors.InvalidPlacement
+	}
+	if path, _ := game.findPath(func(blockedX, blockedY int) bool { return blockedX == x && blockedY == y }); path == nil {
+		return Errors.PathBlocked
+	}
+	return nil
+}
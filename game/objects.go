@@ -17,10 +17,16 @@ type (
 	}
 	RoadObj struct {
 		x, y int
-		// Road index
+		// Road index in `GameState.Roads`.
 		Index int
-		// Valid directions: `up`, `right`, `down`, `left`.
+		// Valid directions: `up`, `right`, `down`, `left`, entrance `start` for spawns and exit `end` for exits.
 		DirEntrance, DirExit string
+		// Indexes of the roads enemies continue on, picked by their weight at forks, empty for exits.
+		Next []int
+		// Chance of being picked at a fork relative to the other branches.
+		Weight float64
+		// Tiles to the nearest exit.
+		Distance int
 	}
 	TowerObj struct {
 		x, y int
@@ -32,35 +38,75 @@ type (
 		Rotation float64
 		// Road objects the tower has range over.
 		effectiveRange []*RoadObj
-		// Tower name
-		Name string
-		// Cost of the tower.
-		Cost int
 		// Owner of the tower by player index.
 		Owner int
-		// Targeting range in tiles.
-		Range int
-		// Damage multiplier.
-		damage int
-		// Progress 1 every second * this.
-		reloadSpeed float64
+		// Upgrade path, valid when tier > 0.
+		Path int
+		// Upgrades bought on the path.
+		Tier int
+		// Coins spent on the tower and its upgrades.
+		Spent int
+		// Valid modes: see `TargetingModes`
+		Targeting string
+		// Remaining time in ms the tower is disabled by a boss.
+		Disabled int
+		TowerType
 	}
 	EnemyObj struct {
 		x, y int
 		// Unique identifier.
 		UID int
-		// Every 1 progress represents 1 tile moved.
+		// Every 1 progress represents 1 tile moved along the route.
 		Progress float64
+		// Road indexes followed from spawn to exit.
+		Route []int
+		// Progress before the last tick.
+		lastProgress float64
+		// Enemy type, see `EnemyTypes`.
+		Type string
 		// Despawn when <= 0.
 		Health int
 		// Starting health.
 		StartHealth int
+		// Absorbs damage before health.
+		Shield int
 		// Amount of coins given once defeated.
 		reward int
 		// Delay spawning by this compared to phase start in ms.
 		startDelay int
 		// Progress 1 every second * this.
 		speedMultiplier float64
+		// Active status effects.
+		Effects []StatusEffect
+		// Health restored by regen and heal not applied yet.
+		regenPending, healPending float64
+		// Boss phases triggered.
+		phase int
+		// Remaining boss burst in ms.
+		burst int
+	}
+	ProjectileObj struct {
+		// Position in tiles.
+		X, Y float64
+		// Position before the last tick.
+		lastX, lastY float64
+		// Unique identifier.
+		UID int
+		// Enemy flown towards by unique identifier, the projectile misses when it is gone.
+		Target int
+		// Last known position of the target.
+		targetX, targetY float64
+		// Tiles every second.
+		Speed float64
+		// Damage on hit.
+		Damage int
+		// Splash radius and falloff, see `TowerType`.
+		Splash, SplashFalloff float64
+		// Applied to every enemy damaged.
+		Effects []Effect
+		// Name and owner of the tower that fired.
+		Tower string
+		Owner int
 	}
 )
 
@@ -127,6 +173,14 @@ func (game *Game) GetCollisionRoads(x, y int) []*RoadObj {
 
 func (obj *TowerObj) Cord() (int, int) { return obj.x, obj.y }
 
+// Next upgrade on path, false when the path is maxed or another path is followed.
+func (obj *TowerObj) NextUpgrade(path int) (TowerUpgrade, bool) {
+	if path < 0 || path >= len(obj.Upgrades) || (obj.Tier > 0 && path != obj.Path) || obj.Tier >= len(obj.Upgrades[path]) {
+		return TowerUpgrade{}, false
+	}
+	return obj.Upgrades[path][obj.Tier], true
+}
+
 func (game *Game) CheckCollisionTowers(x, y int) bool {
 	return slices.ContainsFunc(game.GS.Towers, func(obj *TowerObj) bool { return obj.x == x && obj.y == y })
 }
@@ -147,6 +201,16 @@ func (game *Game) GetCollisionTowers(x, y int) []*TowerObj {
 
 func (obj *EnemyObj) Cord() (int, int) { return obj.x, obj.y }
 
+// Progress interpolated between the last and current tick by `Game.TickProgress`.
+func (obj *EnemyObj) ProgressAt(tickProgress float64) float64 {
+	return obj.lastProgress + ((obj.Progress - obj.lastProgress) * tickProgress)
+}
+
+// Tiles left to the exit of the route.
+func (obj *EnemyObj) Remaining() float64 {
+	return float64(len(obj.Route)) - obj.Progress
+}
+
 func (game *Game) CheckCollisionEnemies(x, y int) bool {
 	return slices.ContainsFunc(game.GS.Enemies, func(obj *EnemyObj) bool { return obj.x == x && obj.y == y })
 }
@@ -164,3 +228,8 @@ func (game *Game) GetCollisionEnemies(x, y int) []*EnemyObj {
 		},
 	)
 }
+
+// Position interpolated between the last and current tick by `Game.TickProgress`.
+func (obj *ProjectileObj) PositionAt(tickProgress float64) (float64, float64) {
+	return obj.lastX + ((obj.X - obj.lastX) * tickProgress), obj.lastY + ((obj.Y - obj.lastY) * tickProgress)
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+func (game *Game) fire(tower *TowerObj, enemy *EnemyObj) {
+	x, y := float64(tower.x), float64(tower.y)
+	projectile := &ProjectileObj{
+		X: x, Y: y, lastX: x, lastY: y,
+		Target: enemy.UID, targetX: float64(enemy.x), targetY: float64(enemy.y),
+		Speed:  tower.ProjectileSpeed,
+		Damage: tower.Damage,
+		Splash: tower.Splash, SplashFalloff: tower.SplashFalloff,
+		Effects: tower.Effects,
+		Tower:   tower.Name, Owner: tower.Owner,
+	}
+	if projectile.Speed <= 0 {
+		projectile.X, projectile.Y = projectile.targetX, projectile.targetY
+		game.impact(projectile, enemy)
+		return
+	}
+
+	projectile.UID = game.newUID()
+	game.GS.Projectiles = append(game.GS.Projectiles, projectile)
+}
+
+func (game *Game) moveProjectiles(delta time.Duration) {
+	toPop := []int{}
+	for i, projectile := range game.GS.Projectiles {
+		projectile.lastX, projectile.lastY = projectile.X, projectile.Y
+
+		var target *EnemyObj
+		if j := slices.IndexFunc(game.GS.Enemies, func(obj *EnemyObj) bool { return obj.UID == projectile.Target }); j >= 0 {
+			target = game.GS.Enemies[j]
+			projectile.targetX, projectile.targetY = float64(target.x), float64(target.y)
+		}
+
+		dx, dy := projectile.targetX-projectile.X, projectile.targetY-projectile.Y
+		if distance, step := math.Hypot(dx, dy), delta.Seconds()*projectile.Speed; step < distance {
+			projectile.X += (dx / distance) * step
+			projectile.Y += (dy / distance) * step
+			continue
+		}
+
+		projectile.X, projectile.Y = projectile.targetX, projectile.targetY
+		toPop = append(toPop, i)
+		game.impact(projectile, target)
+	}
+	slices.Reverse(toPop)
+	for _, i := range toPop {
+		game.GS.Projectiles = slices.Delete(game.GS.Projectiles, i, i+1)
+	}
+}
+
+// Hit the target or with splash every enemy around the projectile, target is nil when it is gone.
+func (game *Game) impact(projectile *ProjectileObj, target *EnemyObj) {
+	if projectile.Splash <= 0 {
+		if target != nil {
+			game.hit(target, projectile.Damage, projectile)
+		}
+		return
+	}
+
+	for _, enemy := range slices.Clone(game.GS.Enemies) {
+		if enemy.startDelay > 0 {
+			continue
+		}
+		// Towers only stand in the lane of their owner, splash stays within it.
+		if game.GC.Mode == "versus" && game.laneOf(enemy.x) != projectile.Owner {
+			continue
+		}
+		distance := math.Hypot(float64(enemy.x)-projectile.X, float64(enemy.y)-projectile.Y)
+		if distance > projectile.Splash {
+			continue
+		}
+		damage := float64(projectile.Damage) * (1 - (projectile.SplashFalloff * (distance / projectile.Splash)))
+		game.hit(enemy, int(math.Round(damage)), projectile)
+	}
+}
+
+func (game *Game) hit(enemy *EnemyObj, damage int, projectile *ProjectileObj) {
+	if damage > 0 {
+		damage = max(1, damage+enemy.shred()-enemy.EnemyType().Armour)
+	}
+	game.damageEnemy(enemy, damage, projectile.Tower, projectile.Owner)
+	if enemy.Health <= 0 {
+		return
+	}
+	for _, effect := range projectile.Effects {
+		enemy.applyEffect(effect, projectile.Tower, projectile.Owner)
+	}
+}
+
+func (game *Game) damageEnemy(enemy *EnemyObj, damage int, tower string, owner int) {
+	absorbed := min(enemy.Shield, damage)
+	enemy.Shield -= absorbed
+	damage = min(enemy.Health, damage-absorbed)
+	enemy.Health -= damage
+	game.GS.Stats.Damage[tower] += absorbed + damage
+	if owner >= 0 && owner < len(game.Players) {
+		game.Players[owner].Stats.Damage += absorbed + damage
+	}
+
+	if enemy.Health > 0 {
+		game.bossPhases(enemy)
+		return
+	}
+
+	if split := enemy.EnemyType().Split; split > 0 {
+		game.spawnBehind(enemy, EnemyTypes[0], split, enemy.StartHealth/2, enemy.reward/2)
+	}
+	game.reward(enemy.reward, owner)
+	game.GS.Stats.CoinsEarned += enemy.reward
+	game.GS.Enemies = slices.DeleteFunc(game.GS.Enemies, func(obj *EnemyObj) bool { return obj.UID == enemy.UID })
+}
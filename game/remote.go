@@ -0,0 +1,37 @@
+package game
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Forward commands to a game hosted elsewhere instead of applying them, the game is then only updated by `Game.Sync`.
+//
+// Must be called before the game is shared with other goroutines.
+func (game *Game) SetRemote(fn func(Command) error) {
+	game.remote = fn
+}
+
+// Apply a command received from another process, such as a player connected to a server.
+func (game *Game) Apply(cmd Command) error {
+	return game.command(cmd)
+}
+
+// Replace the state of a remote game with a snapshot of the hosting game written by `Game.Save`.
+func (game *Game) Sync(r io.Reader) error {
+	save := saveFile{}
+	if err := json.NewDecoder(r).Decode(&save); err != nil {
+		return err
+	}
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if game.remote == nil {
+		return Errors.NotRemote
+	} else if game.GS.State == "stopped" {
+		// Left by the player, snapshots still in flight are dropped.
+		return nil
+	}
+	return game.restore(save)
+}
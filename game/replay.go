@@ -0,0 +1,190 @@
+package game
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type (
+	Command struct {
+		// Tick the command was applied on.
+		Tick int
+		// Valid kinds: `start`, `player`, `pause`, `speed`, `round`, `place`, `destroy`, `upgrade`, `target`, `obstacle`, `gift`, `send`
+		Kind string
+		// Tower name, targeting mode or sent enemy.
+		Name string `json:",omitempty"`
+		// Target tile and player index.
+		X, Y, PID int
+		// Game speed, upgrade path or gifted coins.
+		Value int `json:",omitempty"`
+		// Player index receiving a gift or sent enemy.
+		Target int `json:",omitempty"`
+	}
+
+	replayFile struct {
+		// Incremented on incompatible changes to the format.
+		Version int
+		// Snapshot the commands are applied to.
+		Start    saveFile
+		Commands []Command
+	}
+)
+
+const replayVersion = 1
+
+func (game *Game) command(cmd Command) error {
+	if game.remote != nil {
+		return game.remote(cmd)
+	}
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.record(cmd)
+}
+
+func (game *Game) record(cmd Command) error {
+	if game.replay != nil {
+		// Only playback controls are allowed in replays.
+		if cmd.Kind != "pause" && cmd.Kind != "speed" {
+			return Errors.ReplayReadOnly
+		}
+		return game.apply(cmd)
+	}
+
+	cmd.Tick = game.GS.Tick
+	if err := game.apply(cmd); err != nil {
+		return err
+	}
+	game.commands = append(game.commands, cmd)
+	return nil
+}
+
+func (game *Game) apply(cmd Command) error {
+	switch cmd.Kind {
+	case "start":
+		return game.start()
+	case "player":
+		game.addPlayer()
+		return nil
+	case "pause":
+		game.togglePause()
+		return nil
+	case "speed":
+		game.setGameSpeed(cmd.Value)
+		return nil
+	case "round":
+		return game.startRound()
+	case "place":
+		return game.placeTower(cmd.Name, cmd.X, cmd.Y, cmd.PID)
+	case "destroy":
+		return game.destroyTower(cmd.X, cmd.Y, cmd.PID)
+	case "upgrade":
+		return game.upgradeTower(cmd.X, cmd.Y, cmd.PID, cmd.Value)
+	case "target":
+		return game.setTargeting(cmd.X, cmd.Y, cmd.PID, cmd.Name)
+	case "obstacle":
+		return game.destroyObstacle(cmd.X, cmd.Y, cmd.PID)
+	case "gift":
+		return game.giftCoins(cmd.PID, cmd.Target, cmd.Value)
+	case "send":
+		return game.sendEnemy(cmd.Name, cmd.PID, cmd.Target)
+	}
+	return Errors.InvalidCommand
+}
+
+func (game *Game) startRecording() {
+	game.recordStart, _ = game.snapshot()
+	game.commands = []Command{}
+}
+
+// Write every command since the game was created or restored, playable with `LoadReplay`.
+func (game *Game) SaveReplay(w io.Writer) error {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	if game.replay != nil {
+		return json.NewEncoder(w).Encode(game.replay)
+	}
+	return json.NewEncoder(w).Encode(replayFile{
+		Version:  replayVersion,
+		Start:    game.recordStart,
+		Commands: game.commands,
+	})
+}
+
+// Read a replay written by `Game.SaveReplay`, the game plays back the recorded commands and rejects others.
+func LoadReplay(r io.Reader) (*Game, error) {
+	replay := &replayFile{}
+	if err := json.NewDecoder(r).Decode(replay); err != nil {
+		return nil, err
+	}
+	if replay.Version != replayVersion {
+		return nil, Errors.InvalidReplay
+	}
+
+	game := NewGame(GameConfig{})
+	if err := game.restore(replay.Start); err != nil {
+		return nil, err
+	}
+	game.replay = replay
+	game.applyReplay()
+
+	return game, nil
+}
+
+// Jump to tick of a replay, seeking backwards replays from the start.
+func (game *Game) Seek(tick int) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if game.replay == nil {
+		return Errors.NotReplay
+	}
+
+	paused, gameSpeed := game.GS.State == "paused", game.GC.GameSpeed
+	if tick < game.GS.Tick {
+		if err := game.restore(game.replay.Start); err != nil {
+			return err
+		}
+		game.GC.GameSpeed = gameSpeed
+		game.replayIndex = 0
+		game.applyReplay()
+	}
+
+	if game.GS.State == "paused" {
+		game.GS.State = "started"
+	}
+	for game.GS.Tick < tick && game.GS.State == "started" {
+		game.step()
+	}
+	game.applyReplay()
+	if paused && game.GS.State == "started" {
+		game.GS.State = "paused"
+	}
+	game.lag = 0
+
+	return nil
+}
+
+func (game *Game) applyReplay() {
+	if game.replay == nil {
+		return
+	}
+	for ; game.replayIndex < len(game.replay.Commands); game.replayIndex++ {
+		cmd := game.replay.Commands[game.replayIndex]
+		if cmd.Tick > game.GS.Tick {
+			return
+		}
+		// Playback controls are left to the viewer.
+		if cmd.Kind == "pause" || cmd.Kind == "speed" {
+			continue
+		}
+		_ = game.apply(cmd)
+	}
+}
+
+func (game *Game) step() {
+	game.applyReplay()
+	game.iterate(TickDuration)
+}
@@ -0,0 +1,40 @@
+package game
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReplaySeek(t *testing.T) {
+	gm, pid := newTestGame(t, "roads", 5)
+	play(gm, pid, 1)
+	if err := gm.StartRound(); err != nil {
+		t.Fatal(err)
+	}
+	gm.Step(100)
+	mid, midTick := saved(t, gm), gm.GS.Tick
+	play(gm, pid, 2)
+	end, endTick := saved(t, gm), gm.GS.Tick
+
+	buf := &bytes.Buffer{}
+	if err := gm.SaveReplay(buf); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := LoadReplay(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Forwards to the end and backwards to the middle, replaying from the start.
+	for _, seek := range []struct {
+		tick int
+		want []byte
+	}{{endTick, end}, {midTick, mid}} {
+		if err := replay.Seek(seek.tick); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(saved(t, replay), seek.want) {
+			t.Errorf("replay at tick %v differs from the live game", seek.tick)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package game
+
+import (
+	"slices"
+)
+
+var directions = [4]string{"up", "right", "down", "left"}
+
+// Direction pointing back at dir, empty for `start` and `end`.
+func Opposite(dir string) string {
+	switch dir {
+	case "up":
+		return "down"
+	case "right":
+		return "left"
+	case "down":
+		return "up"
+	case "left":
+		return "right"
+	}
+	return ""
+}
+
+// Tile next to x, y in dir, wrapping around the field edges.
+func Neighbour(x, y int, dir string, width, height int) (int, int) {
+	switch dir {
+	case "up":
+		y -= 1
+		if y < 0 {
+			y = height - 1
+		}
+	case "right":
+		x += 1
+		if x >= width {
+			x = 0
+		}
+	case "down":
+		y += 1
+		if y >= height {
+			y = 0
+		}
+	case "left":
+		x -= 1
+		if x < 0 {
+			x = width - 1
+		}
+	}
+	return x, y
+}
+
+// Spawn points, roads entered from `start`.
+func (game *Game) Spawns() []*RoadObj {
+	return slices.DeleteFunc(slices.Clone(game.GS.Roads), func(obj *RoadObj) bool { return obj.DirEntrance != "start" })
+}
+
+// Add extra spawns merging into and forks splitting off the main road of `genRoads`.
+func (game *Game) genBranches() {
+	main := len(game.GS.Roads)
+	for i := 0; i < (game.GC.Spawns-1)*8 && len(game.Spawns()) < game.GC.Spawns; i++ {
+		x, y := game.rng.IntN(game.GC.FieldWidth), game.rng.IntN(game.GC.FieldHeight)
+		if game.CheckCollisions(x, y) {
+			continue
+		}
+		// Merging into the first half keeps routes from new spawns at least half as long.
+		tiles, into := game.genBranch(x, y, "start", func(obj *RoadObj) bool { return obj.Index < main/2 })
+		if into != nil {
+			game.addBranch(tiles, into)
+		}
+	}
+
+	for i, forks := 0, 0; i < game.GC.Forks*8 && forks < game.GC.Forks && main > 4; i++ {
+		from := game.GS.Roads[1+game.rng.IntN(main-4)]
+		dir := directions[game.rng.IntN(4)]
+		if dir == from.DirEntrance || dir == from.DirExit {
+			continue
+		}
+		x, y := Neighbour(from.x, from.y, dir, game.GC.FieldWidth, game.GC.FieldHeight)
+		if game.CheckCollisions(x, y) {
+			continue
+		}
+		tiles, into := game.genBranch(x, y, Opposite(dir), func(obj *RoadObj) bool { return obj.Index > from.Index+2 && obj.Index < main })
+		// Branches ending in their own exit are kept at least half as long as the rest of the main road.
+		if len(tiles) < 2 || (into == nil && len(tiles) < (main-from.Index)/2) {
+			continue
+		}
+		from.Next = append(from.Next, tiles[0].Index)
+		game.addBranch(tiles, into)
+		forks++
+	}
+
+	game.updateRoadDistances()
+}
+
+// Random walk from x, y until it runs into a road accepted by merge, ends in an exit when into is nil.
+func (game *Game) genBranch(x, y int, dirEntrance string, merge func(obj *RoadObj) bool) (tiles []*RoadObj, into *RoadObj) {
+	dir := directions[game.rng.IntN(4)]
+	if dirEntrance != "start" {
+		dir = Opposite(dirEntrance)
+	}
+	occupied := func(x, y int) bool {
+		return game.CheckCollisions(x, y) || slices.ContainsFunc(tiles, func(obj *RoadObj) bool { return obj.x == x && obj.y == y })
+	}
+
+	index := len(game.GS.Roads)
+	for i, retries := 0, 0; i < game.GC.FieldWidth+game.GC.FieldHeight && retries < 8; i++ {
+		oldDir := dir
+		if n := game.rng.IntN(8); n < 4 && directions[n] != Opposite(oldDir) {
+			dir = directions[n]
+		}
+		nextX, nextY := Neighbour(x, y, dir, game.GC.FieldWidth, game.GC.FieldHeight)
+
+		if roads := slices.DeleteFunc(game.GetCollisionRoads(nextX, nextY), func(obj *RoadObj) bool { return !merge(obj) }); len(roads) > 0 {
+			tiles = append(tiles, &RoadObj{x: x, y: y, Index: index + len(tiles), DirEntrance: dirEntrance, DirExit: dir})
+			return tiles, roads[0]
+		}
+		if occupied(nextX, nextY) {
+			dir = oldDir
+			retries++
+			continue
+		}
+		retries = 0
+
+		tiles = append(tiles, &RoadObj{x: x, y: y, Index: index + len(tiles), DirEntrance: dirEntrance, DirExit: dir})
+		x, y, dirEntrance = nextX, nextY, Opposite(dir)
+	}
+
+	if dirEntrance == "start" || occupied(x, y) {
+		return tiles, nil
+	}
+	return append(tiles, &RoadObj{x: x, y: y, Index: index + len(tiles), DirEntrance: dirEntrance, DirExit: "end"}), nil
+}
+
+func (game *Game) addBranch(tiles []*RoadObj, into *RoadObj) {
+	for i, tile := range tiles {
+		tile.Weight = 1
+		if i < len(tiles)-1 {
+			tile.Next = []int{tiles[i+1].Index}
+		} else if into != nil {
+			tile.Next = []int{into.Index}
+		}
+		game.GS.Roads = append(game.GS.Roads, tile)
+	}
+}
+
+// Set `RoadObj.Distance` by walking back from every exit.
+func (game *Game) updateRoadDistances() {
+	queue := []*RoadObj{}
+	for _, road := range game.GS.Roads {
+		road.Distance = -1
+		if len(road.Next) == 0 {
+			road.Distance = 0
+			queue = append(queue, road)
+		}
+	}
+	for len(queue) > 0 {
+		road := queue[0]
+		queue = queue[1:]
+		for _, obj := range game.GS.Roads {
+			if obj.Distance < 0 && slices.Contains(obj.Next, road.Index) {
+				obj.Distance = road.Distance + 1
+				queue = append(queue, obj)
+			}
+		}
+	}
+}
+
+// Roads followed from spawn to an exit, forks are picked at random by `RoadObj.Weight`.
+func (game *Game) genRoute(spawn *RoadObj) []int {
+	route := []int{spawn.Index}
+	for road := spawn; len(road.Next) > 0 && len(route) <= len(game.GS.Roads); {
+		next := road.Next[0]
+		if len(road.Next) > 1 {
+			total := 0.0
+			for _, i := range road.Next {
+				total += game.GS.Roads[i].Weight
+			}
+			pick := game.rng.Float64() * total
+			for _, i := range road.Next {
+				if next = i; pick < game.GS.Roads[i].Weight {
+					break
+				}
+				pick -= game.GS.Roads[i].Weight
+			}
+		}
+		road = game.GS.Roads[next]
+		route = append(route, next)
+	}
+	return route
+}
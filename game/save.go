@@ -0,0 +1,329 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"slices"
+)
+
+type (
+	saveFile struct {
+		// Incremented on incompatible changes to the format.
+		Version int
+		Config  GameConfig
+		State   saveState
+		Players []Player
+		// Last allocated unique identifier.
+		UID int64
+		// Marshaled PCG state.
+		RNG []byte
+	}
+	saveState struct {
+		State, Phase        string
+		Round, Health, Tick int
+		Lanes, Winner       int
+		Obstacles           []saveObstacle
+		Roads               []saveRoad
+		Towers              []saveTower
+		Enemies             []saveEnemy
+		Projectiles         []saveProjectile
+		Stats               GameStats
+	}
+	saveObstacle struct {
+		X, Y, UID, Cost int
+	}
+	saveRoad struct {
+		X, Y, Index          int
+		DirEntrance, DirExit string
+		Next                 []int
+		Weight               float64
+		Distance             int
+	}
+	saveTower struct {
+		X, Y, UID, Owner         int
+		Path, Tier, Spent        int
+		Targeting                string
+		Disabled                 int
+		ReloadProgress, Rotation float64
+		TowerType
+	}
+	saveProjectile struct {
+		X, Y, LastX, LastY float64
+		UID, Target        int
+		TargetX, TargetY   float64
+		Speed              float64
+		Damage             int
+		Splash, Falloff    float64
+		Effects            []Effect
+		Tower              string
+		Owner              int
+	}
+	saveEffect struct {
+		Effect
+		Remaining int
+		Tower     string
+		Owner     int
+		Pending   float64
+	}
+	saveEnemy struct {
+		X, Y, UID                 int
+		Type                      string
+		Progress, LastProgress    float64
+		Route                     []int
+		Health, StartHealth       int
+		Shield                    int
+		Reward, StartDelay        int
+		SpeedMultiplier           float64
+		Effects                   []saveEffect
+		RegenPending, HealPending float64
+		Phase, Burst              int
+	}
+)
+
+const saveVersion = 12
+
+// Write a snapshot of the game that can be restored with `Load` or `Game.Restore`.
+func (game *Game) Save(w io.Writer) error {
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	save, err := game.snapshot()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(save)
+}
+
+func (game *Game) snapshot() (saveFile, error) {
+	rng, err := game.rngSrc.MarshalBinary()
+	if err != nil {
+		return saveFile{}, err
+	}
+
+	save := saveFile{
+		Version: saveVersion,
+		Config:  game.GC,
+		State: saveState{
+			State: game.GS.State, Phase: game.GS.Phase,
+			Round: game.GS.Round, Health: game.GS.Health, Tick: game.GS.Tick,
+			Lanes: game.GS.Lanes, Winner: game.GS.Winner,
+			Obstacles: []saveObstacle{}, Roads: []saveRoad{}, Towers: []saveTower{}, Enemies: []saveEnemy{},
+			Projectiles: []saveProjectile{},
+			Stats: GameStats{
+				CoinsEarned: game.GS.Stats.CoinsEarned,
+				Damage:      maps.Clone(game.GS.Stats.Damage),
+				Leaks:       slices.Clone(game.GS.Stats.Leaks),
+			},
+		},
+		Players: slices.Clone(game.Players),
+		UID:     game.uid.Load(),
+		RNG:     rng,
+	}
+	for _, obj := range game.GS.Obstacles {
+		save.State.Obstacles = append(save.State.Obstacles, saveObstacle{X: obj.x, Y: obj.y, UID: obj.UID, Cost: obj.Cost})
+	}
+	for _, obj := range game.GS.Roads {
+		save.State.Roads = append(save.State.Roads, saveRoad{
+			X: obj.x, Y: obj.y, Index: obj.Index,
+			DirEntrance: obj.DirEntrance, DirExit: obj.DirExit,
+			Next: slices.Clone(obj.Next), Weight: obj.Weight, Distance: obj.Distance,
+		})
+	}
+	for _, obj := range game.GS.Towers {
+		save.State.Towers = append(save.State.Towers, saveTower{
+			X: obj.x, Y: obj.y, UID: obj.UID, Owner: obj.Owner,
+			Path: obj.Path, Tier: obj.Tier, Spent: obj.Spent,
+			Targeting: obj.Targeting, Disabled: obj.Disabled,
+			ReloadProgress: obj.ReloadProgress, Rotation: obj.Rotation,
+			TowerType: obj.TowerType,
+		})
+	}
+	for _, obj := range game.GS.Enemies {
+		effects := []saveEffect{}
+		for _, effect := range obj.Effects {
+			effects = append(effects, saveEffect{Effect: effect.Effect, Remaining: effect.Remaining, Tower: effect.Tower, Owner: effect.Owner, Pending: effect.pending})
+		}
+		save.State.Enemies = append(save.State.Enemies, saveEnemy{
+			X: obj.x, Y: obj.y, UID: obj.UID,
+			Type:     obj.Type,
+			Progress: obj.Progress, LastProgress: obj.lastProgress,
+			Route:  slices.Clone(obj.Route),
+			Health: obj.Health, StartHealth: obj.StartHealth,
+			Shield: obj.Shield,
+			Reward: obj.reward, StartDelay: obj.startDelay,
+			SpeedMultiplier: obj.speedMultiplier,
+			Effects:         effects,
+			RegenPending:    obj.regenPending, HealPending: obj.healPending,
+			Phase: obj.phase, Burst: obj.burst,
+		})
+	}
+	for _, obj := range game.GS.Projectiles {
+		save.State.Projectiles = append(save.State.Projectiles, saveProjectile{
+			X: obj.X, Y: obj.Y, LastX: obj.lastX, LastY: obj.lastY,
+			UID: obj.UID, Target: obj.Target,
+			TargetX: obj.targetX, TargetY: obj.targetY,
+			Speed: obj.Speed, Damage: obj.Damage,
+			Splash: obj.Splash, Falloff: obj.SplashFalloff,
+			Effects: slices.Clone(obj.Effects),
+			Tower:   obj.Tower, Owner: obj.Owner,
+		})
+	}
+
+	return save, nil
+}
+
+// References between objects are in range and the config passes `LoadTowers` and `LoadWaves`, the game indexes by them without checking.
+func (save saveFile) valid() bool {
+	state := save.State
+	if state.Round < 0 || len(state.Stats.Leaks) < state.Round || state.Lanes < 0 || state.Lanes > len(save.Players) {
+		return false
+	} else if state.Round < 1 && len(state.Enemies) > 0 {
+		return false
+	} else if save.Config.Waves == nil {
+		return false
+	}
+	if data, err := json.Marshal(save.Config.Towers); err != nil {
+		return false
+	} else if _, err := LoadTowers(bytes.NewReader(data)); err != nil {
+		return false
+	}
+	if data, err := json.Marshal(save.Config.Waves); err != nil {
+		return false
+	} else if _, err := LoadWaves(bytes.NewReader(data)); err != nil {
+		return false
+	}
+	outOfRoads := func(i int) bool { return i < 0 || i >= len(state.Roads) }
+	for i, obj := range state.Roads {
+		if obj.Index != i || slices.ContainsFunc(obj.Next, outOfRoads) {
+			return false
+		}
+	}
+	for _, obj := range state.Towers {
+		i := slices.IndexFunc(save.Config.Towers, func(towerType TowerType) bool { return towerType.Name == obj.Name })
+		if i < 0 || obj.Owner < 0 || obj.Owner >= len(save.Players) || obj.Tier < 0 || obj.Path < 0 || (obj.Tier > 0 && (obj.Path >= len(save.Config.Towers[i].Upgrades) || obj.Tier > len(save.Config.Towers[i].Upgrades[obj.Path]))) {
+			return false
+		}
+	}
+	for _, obj := range state.Enemies {
+		if _, ok := enemyType(obj.Type); !ok || obj.Progress < 0 || obj.LastProgress < 0 || slices.ContainsFunc(obj.Route, outOfRoads) {
+			return false
+		}
+	}
+	return true
+}
+
+// Read a game written by `Game.Save`.
+func Load(r io.Reader) (*Game, error) {
+	game := NewGame(GameConfig{})
+	if err := game.Restore(r); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// Replace the game with one written by `Game.Save`, safe to call while the game is running.
+func (game *Game) Restore(r io.Reader) error {
+	save := saveFile{}
+	if err := json.NewDecoder(r).Decode(&save); err != nil {
+		return err
+	}
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if game.replay != nil {
+		return Errors.ReplayReadOnly
+	} else if game.remote != nil {
+		return Errors.RemoteGame
+	}
+	return game.restore(save)
+}
+
+func (game *Game) restore(save saveFile) error {
+	if save.Version != saveVersion || !save.valid() {
+		return Errors.InvalidSave
+	}
+	rngSrc := &rand.PCG{}
+	if err := rngSrc.UnmarshalBinary(save.RNG); err != nil {
+		return err
+	}
+
+	game.GC = save.Config
+	game.GS = GameState{
+		State: save.State.State, Phase: save.State.Phase,
+		Round: save.State.Round, Health: save.State.Health, Tick: save.State.Tick,
+		Lanes: save.State.Lanes, Winner: save.State.Winner,
+		Obstacles: []*ObstacleObj{}, Roads: []*RoadObj{}, Towers: []*TowerObj{}, Enemies: []*EnemyObj{},
+		Projectiles: []*ProjectileObj{},
+		Stats: GameStats{
+			CoinsEarned: save.State.Stats.CoinsEarned,
+			Damage:      maps.Clone(save.State.Stats.Damage),
+			Leaks:       slices.Clone(save.State.Stats.Leaks),
+		},
+	}
+	if game.GS.Stats.Damage == nil {
+		game.GS.Stats.Damage = map[string]int{}
+	}
+	game.Players = slices.Clone(save.Players)
+	game.uid.Store(save.UID)
+	game.rng, game.rngSrc = rand.New(rngSrc), rngSrc
+	game.lag, game.mazeDepth = 0, nil
+
+	for _, obj := range save.State.Obstacles {
+		game.GS.Obstacles = append(game.GS.Obstacles, &ObstacleObj{x: obj.X, y: obj.Y, UID: obj.UID, Cost: obj.Cost})
+	}
+	for _, obj := range save.State.Roads {
+		game.GS.Roads = append(game.GS.Roads, &RoadObj{
+			x: obj.X, y: obj.Y, Index: obj.Index,
+			DirEntrance: obj.DirEntrance, DirExit: obj.DirExit,
+			Next: slices.Clone(obj.Next), Weight: obj.Weight, Distance: obj.Distance,
+		})
+	}
+	for _, obj := range save.State.Towers {
+		tower := &TowerObj{
+			x: obj.X, y: obj.Y, UID: obj.UID, Owner: obj.Owner,
+			Path: obj.Path, Tier: obj.Tier, Spent: obj.Spent,
+			Targeting: obj.Targeting, Disabled: obj.Disabled,
+			ReloadProgress: obj.ReloadProgress, Rotation: obj.Rotation,
+			TowerType: obj.TowerType,
+		}
+		game.updateEffectiveRange(tower)
+		game.GS.Towers = append(game.GS.Towers, tower)
+	}
+	for _, obj := range save.State.Enemies {
+		effects := []StatusEffect{}
+		for _, effect := range obj.Effects {
+			effects = append(effects, StatusEffect{Effect: effect.Effect, Remaining: effect.Remaining, Tower: effect.Tower, Owner: effect.Owner, pending: effect.Pending})
+		}
+		game.GS.Enemies = append(game.GS.Enemies, &EnemyObj{
+			x: obj.X, y: obj.Y, UID: obj.UID,
+			Type:     obj.Type,
+			Progress: obj.Progress, lastProgress: obj.LastProgress,
+			Route:  slices.Clone(obj.Route),
+			Health: obj.Health, StartHealth: obj.StartHealth,
+			Shield: obj.Shield,
+			reward: obj.Reward, startDelay: obj.StartDelay,
+			speedMultiplier: obj.SpeedMultiplier,
+			Effects:         effects,
+			regenPending:    obj.RegenPending, healPending: obj.HealPending,
+			phase: obj.Phase, burst: obj.Burst,
+		})
+	}
+	for _, obj := range save.State.Projectiles {
+		game.GS.Projectiles = append(game.GS.Projectiles, &ProjectileObj{
+			X: obj.X, Y: obj.Y, lastX: obj.LastX, lastY: obj.LastY,
+			UID: obj.UID, Target: obj.Target,
+			targetX: obj.TargetX, targetY: obj.TargetY,
+			Speed: obj.Speed, Damage: obj.Damage,
+			Splash: obj.Splash, SplashFalloff: obj.Falloff,
+			Effects: slices.Clone(obj.Effects),
+			Tower:   obj.Tower, Owner: obj.Owner,
+		})
+	}
+
+	game.recordStart, game.commands = save, []Command{}
+	return nil
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSaveLoadContinues(t *testing.T) {
+	for _, mode := range []string{"roads", "maze"} {
+		gm, pid := newTestGame(t, mode, 3)
+		play(gm, pid, 2)
+		if err := gm.StartRound(); err != nil {
+			t.Fatal(err)
+		}
+		gm.Step(100)
+		if len(gm.GS.Enemies) == 0 {
+			t.Fatalf("%v: no enemies mid-wave", mode)
+		}
+
+		loaded, err := Load(bytes.NewReader(saved(t, gm)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		play(gm, pid, 2)
+		play(loaded, pid, 2)
+		if !bytes.Equal(saved(t, gm), saved(t, loaded)) {
+			t.Errorf("%v: loaded game differs from the game it was saved from", mode)
+		}
+	}
+}
+
+func TestLoadWithoutWaves(t *testing.T) {
+	gm, pid := newTestGame(t, "roads", 3)
+	play(gm, pid, 1)
+
+	save := saveFile{}
+	if err := json.Unmarshal(saved(t, gm), &save); err != nil {
+		t.Fatal(err)
+	}
+	save.Config.Waves = nil
+	data, err := json.Marshal(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(bytes.NewReader(data)); err != Errors.InvalidSave {
+		t.Fatalf("loading a save without waves: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package game
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+)
+
+type (
+	TowerType struct {
+		// Unique tower name.
+		Name string `json:"name"`
+		// Cost of the tower.
+		Cost int `json:"cost"`
+		// Targeting range in tiles.
+		Range int `json:"range"`
+		// Damage per hit.
+		Damage int `json:"damage"`
+		// Progress 1 every second * this.
+		ReloadSpeed float64 `json:"reloadSpeed"`
+		// Tiles every second shots fly, hits instantly when 0.
+		ProjectileSpeed float64 `json:"projectileSpeed,omitempty"`
+		// Radius in tiles around the impact damaging every enemy, hits a single enemy when 0.
+		Splash float64 `json:"splash,omitempty"`
+		// Part of the damage lost at the edge of the splash radius, in range [0, 1].
+		SplashFalloff float64 `json:"splashFalloff,omitempty"`
+		// Applied to every enemy hit.
+		Effects []Effect `json:"effects,omitempty"`
+		// Targets stealth enemies.
+		Detection bool `json:"detection,omitempty"`
+		// Row in the towers texture.
+		Sprite int `json:"sprite"`
+		// Drawn by terminal renderers, 2 cells wide.
+		Glyph string `json:"glyph"`
+		// Upgrade paths of tiers bought in order, a tower follows a single path.
+		Upgrades [][]TowerUpgrade `json:"upgrades,omitempty"`
+	}
+	TowerUpgrade struct {
+		// Shown to players.
+		Name string `json:"name"`
+		// Cost of the upgrade, counted towards the refund.
+		Cost int `json:"cost"`
+		// Added to the tower.
+		Range       int     `json:"range,omitempty"`
+		Damage      int     `json:"damage,omitempty"`
+		ReloadSpeed float64 `json:"reloadSpeed,omitempty"`
+		Splash      float64 `json:"splash,omitempty"`
+	}
+)
+
+const (
+	defaultGlyph = " 󰚁"
+	// Upgrade paths reachable from the clients.
+	maxUpgradePaths = 3
+)
+
+var (
+	//go:embed towers.json
+	towersJSON []byte
+
+	// Built-in tower types, used when `GameConfig.Towers` is not set.
+	Towers = func() []TowerType {
+		towers, err := LoadTowers(bytes.NewReader(towersJSON))
+		if err != nil {
+			panic(err)
+		}
+		return towers
+	}()
+)
+
+// Read and validate tower types from a JSON array, errors point at the offending entry.
+func LoadTowers(r io.Reader) ([]TowerType, error) {
+	entries := []json.RawMessage{}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, errors.New("towers: " + err.Error())
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("towers: no towers defined")
+	}
+
+	towers := []TowerType{}
+	for i, entry := range entries {
+		tower := TowerType{}
+		dec := json.NewDecoder(bytes.NewReader(entry))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&tower); err != nil {
+			return nil, towerError(i, tower.Name, err.Error())
+		}
+
+		switch {
+		case tower.Name == "":
+			return nil, towerError(i, tower.Name, "name is empty")
+		case slices.ContainsFunc(towers, func(obj TowerType) bool { return obj.Name == tower.Name }):
+			return nil, towerError(i, tower.Name, "name is not unique")
+		case tower.Cost < 0:
+			return nil, towerError(i, tower.Name, "cost is negative")
+		case tower.Range < 0:
+			return nil, towerError(i, tower.Name, "range is negative")
+		case tower.Damage < 0:
+			return nil, towerError(i, tower.Name, "damage is negative")
+		case tower.ReloadSpeed <= 0:
+			return nil, towerError(i, tower.Name, "reloadSpeed is not positive")
+		case tower.ProjectileSpeed < 0:
+			return nil, towerError(i, tower.Name, "projectileSpeed is negative")
+		case tower.Splash < 0:
+			return nil, towerError(i, tower.Name, "splash is negative")
+		case tower.SplashFalloff < 0 || tower.SplashFalloff > 1:
+			return nil, towerError(i, tower.Name, "splashFalloff is not in range [0, 1]")
+		case tower.Sprite < 0:
+			return nil, towerError(i, tower.Name, "sprite is negative")
+		}
+		if tower.Glyph == "" {
+			tower.Glyph = defaultGlyph
+		}
+		for j, effect := range tower.Effects {
+			if err := effect.validate(); err != nil {
+				return nil, towerError(i, tower.Name, "effects["+strconv.Itoa(j)+"]: "+err.Error())
+			}
+		}
+		if err := tower.validateUpgrades(); err != nil {
+			return nil, towerError(i, tower.Name, err.Error())
+		}
+
+		towers = append(towers, tower)
+	}
+	return towers, nil
+}
+
+func towerError(i int, name, msg string) error {
+	return errors.New("towers[" + strconv.Itoa(i) + "] " + strconv.Quote(name) + ": " + msg)
+}
+
+func (tower TowerType) validateUpgrades() error {
+	if len(tower.Upgrades) > maxUpgradePaths {
+		return errors.New("upgrades has more than " + strconv.Itoa(maxUpgradePaths) + " paths")
+	}
+	for p, path := range tower.Upgrades {
+		if len(path) == 0 {
+			return errors.New("upgrades[" + strconv.Itoa(p) + "] has no tiers")
+		}
+		r, damage, reloadSpeed, splash := tower.Range, tower.Damage, tower.ReloadSpeed, tower.Splash
+		for t, upgrade := range path {
+			r, damage, reloadSpeed, splash = r+upgrade.Range, damage+upgrade.Damage, reloadSpeed+upgrade.ReloadSpeed, splash+upgrade.Splash
+			prefix := "upgrades[" + strconv.Itoa(p) + "][" + strconv.Itoa(t) + "]: "
+			switch {
+			case upgrade.Name == "":
+				return errors.New(prefix + "name is empty")
+			case upgrade.Cost < 0:
+				return errors.New(prefix + "cost is negative")
+			case r < 0:
+				return errors.New(prefix + "range becomes negative")
+			case damage < 0:
+				return errors.New(prefix + "damage becomes negative")
+			case reloadSpeed <= 0:
+				return errors.New(prefix + "reloadSpeed becomes not positive")
+			case splash < 0:
+				return errors.New(prefix + "splash becomes negative")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package game
+
+import "slices"
+
+// Enemy sent to the lane of another player in versus, spawned in their next round.
+type SendType struct {
+	// Enemy type, see `EnemyTypes`.
+	Enemy string
+	// Coins paid by the sender.
+	Cost int
+	// Coins added to the income of the sender, paid at the start of every round.
+	Income int
+	// Health before the multiplier of the enemy type, increased by a fifth every round.
+	Health int
+	// Amount of coins given to the defender once defeated.
+	Reward int
+}
+
+// Valid enemies to send in versus.
+var SendTypes = []SendType{
+	{Enemy: "basic", Cost: 10, Income: 1, Health: 3, Reward: 1},
+	{Enemy: "runner", Cost: 15, Income: 1, Health: 4, Reward: 1},
+	{Enemy: "armoured", Cost: 25, Income: 2, Health: 4, Reward: 2},
+	{Enemy: "regen", Cost: 30, Income: 2, Health: 5, Reward: 3},
+	{Enemy: "stealth", Cost: 40, Income: 3, Health: 4, Reward: 3},
+}
+
+// Delay between sent enemies in ms.
+const sendSpacing = 500
+
+// Lane of the player defending column x in versus, every lane is `GameConfig.FieldWidth` / `GameState.Lanes` wide.
+func (game *Game) laneOf(x int) int {
+	if game.GS.Lanes < 1 {
+		return 0
+	}
+	return min(x/(game.GC.FieldWidth/game.GS.Lanes), game.GS.Lanes-1)
+}
+
+// Player pid has a lane and has not lost.
+func (game *Game) alive(pid int) bool {
+	return pid >= 0 && pid < game.GS.Lanes && game.Players[pid].Phase != "lost"
+}
+
+// Health left of player pid, shared by every player besides in versus.
+func (game *Game) GetHealth(pid int) int {
+	if game.GC.Mode != "versus" || pid < 0 || pid >= len(game.Players) {
+		return game.GS.Health
+	}
+	return game.Players[pid].Health
+}
+
+// Wind a road from the top to the bottom edge through the first lane and repeat it in every lane, every player defends the same road.
+func (game *Game) genLanes() {
+	game.GS.Lanes = len(game.Players)
+	width := game.GC.FieldWidth / game.GS.Lanes
+
+	// Lanes keep a column free on both sides, roads of neighbouring lanes never touch.
+	tiles := [][2]int{}
+	x := 1 + game.rng.IntN(width-2)
+	for y := range game.GC.FieldHeight {
+		tiles = append(tiles, [2]int{x, y})
+		if y == game.GC.FieldHeight-1 || game.rng.IntN(3) > 0 {
+			continue
+		}
+		to := 1 + game.rng.IntN(width-2)
+		for x != to {
+			if x < to {
+				x++
+			} else {
+				x--
+			}
+			tiles = append(tiles, [2]int{x, y})
+		}
+	}
+
+	for lane := range game.GS.Lanes {
+		for i, tile := range tiles {
+			road := &RoadObj{x: tile[0] + (lane * width), y: tile[1], Index: len(game.GS.Roads), DirEntrance: "start", DirExit: "end", Weight: 1}
+			if i > 0 {
+				road.DirEntrance = direction(tile, tiles[i-1])
+			}
+			if i < len(tiles)-1 {
+				road.DirExit = direction(tile, tiles[i+1])
+				road.Next = []int{road.Index + 1}
+			}
+			game.GS.Roads = append(game.GS.Roads, road)
+		}
+	}
+	game.updateRoadDistances()
+}
+
+// Direction of the neighbouring tile to from tile.
+func direction(tile, to [2]int) string {
+	switch {
+	case to[1] < tile[1]:
+		return "up"
+	case to[0] > tile[0]:
+		return "right"
+	case to[1] > tile[1]:
+		return "down"
+	}
+	return "left"
+}
+
+func (game *Game) SendEnemy(name string, pid, to int) error {
+	return game.command(Command{Kind: "send", Name: name, PID: pid, Target: to})
+}
+
+func (game *Game) sendEnemy(name string, pid, to int) error {
+	if game.GS.State != "started" && game.GS.State != "paused" {
+		return Errors.GameStateNotActive
+	} else if game.GC.Mode != "versus" {
+		return Errors.InvalidMode
+	} else if game.GS.Phase == "lost" {
+		return Errors.GamePhaseStopped
+	}
+	if !game.alive(pid) || !game.alive(to) || pid == to {
+		return Errors.InvalidPlayer
+	}
+	i := slices.IndexFunc(SendTypes, func(obj SendType) bool { return obj.Enemy == name })
+	if i < 0 {
+		return Errors.InvalidSelection
+	}
+	if SendTypes[i].Cost > game.Players[pid].Coins {
+		return Errors.InsufficientFunds
+	}
+
+	game.spend(pid, SendTypes[i].Cost)
+	game.Players[pid].Income += SendTypes[i].Income
+	game.Players[pid].Stats.Sent++
+	// Clipped, snapshots share the queue with the players.
+	game.Players[to].Queue = append(slices.Clip(game.Players[to].Queue), name)
+	return nil
+}
+
+// Spawn the round in every lane of a player still playing, with the enemies sent to them, and pay out income.
+func (game *Game) spawnLanes(groups []WaveGroup) {
+	spawns := game.Spawns()
+	for pid := range game.GS.Lanes {
+		if !game.alive(pid) {
+			continue
+		}
+		player := &game.Players[pid]
+		player.Coins += player.Income
+		player.Phase = "defending"
+
+		lane := slices.DeleteFunc(slices.Clone(spawns), func(obj *RoadObj) bool { return game.laneOf(obj.x) != pid })
+		game.spawnEnemies(groups, lane)
+
+		for i, name := range player.Queue {
+			sendType := SendTypes[slices.IndexFunc(SendTypes, func(obj SendType) bool { return obj.Enemy == name })]
+			enemyType, _ := enemyType(sendType.Enemy)
+			spawn := lane[i%len(lane)]
+			x, y := spawn.Cord()
+			health := sendType.Health + (sendType.Health * game.GS.Round / 5)
+			enemy := game.newEnemy(enemyType, x, y, health, sendType.Reward, i*sendSpacing, 1)
+			enemy.Route = game.genRoute(spawn)
+			game.GS.Enemies = append(game.GS.Enemies, enemy)
+		}
+		player.Queue = []string{}
+	}
+}
+
+// Update the phase of every player by the enemies left in their lane, removing the lanes of players without health left.
+func (game *Game) updateLanes() {
+	for pid := range game.GS.Lanes {
+		if !game.alive(pid) {
+			continue
+		}
+		player := &game.Players[pid]
+		if player.Health <= 0 {
+			player.Phase = "lost"
+			game.GS.Enemies = slices.DeleteFunc(game.GS.Enemies, func(obj *EnemyObj) bool { return game.laneOf(obj.x) == pid })
+			continue
+		}
+		player.Phase = "building"
+		if slices.ContainsFunc(game.GS.Enemies, func(obj *EnemyObj) bool { return game.laneOf(obj.x) == pid }) {
+			player.Phase = "defending"
+		}
+	}
+
+	alive := []int{}
+	for pid := range game.GS.Lanes {
+		if game.alive(pid) {
+			alive = append(alive, pid)
+		}
+	}
+	if len(alive) <= 1 {
+		game.GS.Winner = -1
+		if len(alive) == 1 {
+			game.GS.Winner = alive[0]
+		}
+		game.GS.Phase = "lost"
+	}
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"maps"
+	"slices"
+)
+
+// Read only view of a game, renderers draw from a view and spectators can not issue commands through it.
+//
+// Returned objects are copies, modifying them does not change the game.
+type Viewer interface {
+	GetConfig() GameConfig
+	GetState() GameState
+	GetPlayers() []Player
+	GetCoins(pid int) int
+	GetHealth(pid int) int
+	TickProgress() float64
+	Boss() *EnemyObj
+	GetCollisions(x, y int) []GameObj
+	CheckCollisionTowers(x, y int) bool
+	GetCollisionTowers(x, y int) []*TowerObj
+}
+
+// Copy of a game taken by `Game.ReadOnly`, answering from the copy only.
+type view struct {
+	game *Game
+}
+
+// Copy of the game as a `Viewer`, may only be called from within the Run callback or `Game.View`.
+func (game *Game) ReadOnly() Viewer {
+	gc := game.GC
+	gc.Towers = slices.Clone(gc.Towers)
+
+	players := slices.Clone(game.Players)
+	for i := range players {
+		players[i].Queue = slices.Clone(players[i].Queue)
+	}
+
+	gs := game.GS
+	gs.Obstacles, gs.Roads, gs.Towers, gs.Enemies, gs.Projectiles = []*ObstacleObj{}, []*RoadObj{}, []*TowerObj{}, []*EnemyObj{}, []*ProjectileObj{}
+	gs.Stats.Damage, gs.Stats.Leaks = maps.Clone(gs.Stats.Damage), slices.Clone(gs.Stats.Leaks)
+	for _, obj := range game.GS.Obstacles {
+		obstacle := *obj
+		gs.Obstacles = append(gs.Obstacles, &obstacle)
+	}
+	for _, obj := range game.GS.Roads {
+		road := *obj
+		road.Next = slices.Clone(road.Next)
+		gs.Roads = append(gs.Roads, &road)
+	}
+	for _, obj := range game.GS.Towers {
+		tower := *obj
+		tower.effectiveRange = nil
+		gs.Towers = append(gs.Towers, &tower)
+	}
+	for _, obj := range game.GS.Enemies {
+		enemy := *obj
+		enemy.Route, enemy.Effects = slices.Clone(enemy.Route), slices.Clone(enemy.Effects)
+		gs.Enemies = append(gs.Enemies, &enemy)
+	}
+	for _, obj := range game.GS.Projectiles {
+		projectile := *obj
+		projectile.Effects = slices.Clone(projectile.Effects)
+		gs.Projectiles = append(gs.Projectiles, &projectile)
+	}
+
+	return view{game: &Game{GC: gc, GS: gs, Players: players, lag: game.lag}}
+}
+
+func (v view) GetConfig() GameConfig { return v.game.GC }
+
+func (v view) GetState() GameState { return v.game.GS }
+
+func (v view) GetPlayers() []Player { return v.game.Players }
+
+func (v view) GetCoins(pid int) int { return v.game.GetCoins(pid) }
+
+func (v view) GetHealth(pid int) int { return v.game.GetHealth(pid) }
+
+func (v view) TickProgress() float64 { return v.game.TickProgress() }
+
+func (v view) Boss() *EnemyObj { return v.game.Boss() }
+
+func (v view) GetCollisions(x, y int) []GameObj { return v.game.GetCollisions(x, y) }
+
+func (v view) CheckCollisionTowers(x, y int) bool { return v.game.CheckCollisionTowers(x, y) }
+
+func (v view) GetCollisionTowers(x, y int) []*TowerObj { return v.game.GetCollisionTowers(x, y) }
@@ -0,0 +1,262 @@
+package game
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+)
+
+type (
+	WaveSet struct {
+		// Name of the wave set.
+		Name string `json:"name"`
+		// Groups per round, starting at round 1.
+		Rounds [][]WaveGroup `json:"rounds"`
+		// Groups of every round after the scripted rounds.
+		Endless []EndlessGroup `json:"endless"`
+		// Boss ending every so many rounds, no bosses when not set.
+		Boss *BossWave `json:"boss,omitempty"`
+	}
+	WaveGroup struct {
+		// Enemy type, see `EnemyTypes`.
+		Type string `json:"type"`
+		// Amount of enemies.
+		Count int `json:"count"`
+		// Delay between enemies in ms.
+		Spacing int `json:"spacing"`
+		// Starting health.
+		Health int `json:"health"`
+		// Progress 1 every second * this.
+		Speed float64 `json:"speed"`
+		// Amount of coins given once defeated.
+		Reward int `json:"reward"`
+		// Delay after the last enemy of the previous group in ms.
+		Delay int `json:"delay,omitempty"`
+	}
+	// Same as `WaveGroup` with expressions of the round `r` instead of numbers.
+	EndlessGroup struct {
+		Type    string `json:"type"`
+		Count   string `json:"count"`
+		Spacing string `json:"spacing"`
+		Health  string `json:"health"`
+		Speed   string `json:"speed"`
+		Reward  string `json:"reward"`
+		Delay   string `json:"delay,omitempty"`
+	}
+)
+
+const (
+	// Enemies per group at most, endless groups are capped to it.
+	maxGroupCount = 1000
+	// Speed of endless groups at least, slower enemies would hold up the round.
+	minGroupSpeed = 0.1
+)
+
+var (
+	//go:embed waves/*.json
+	wavesFS embed.FS
+
+	// Built-in wave set, used when `GameConfig.Waves` is not set.
+	Waves = func() *WaveSet {
+		waves, err := BuiltinWaves("default")
+		if err != nil {
+			panic(err)
+		}
+		return waves
+	}()
+)
+
+// Names of the built-in wave sets.
+func BuiltinWaveNames() []string {
+	names := []string{}
+	entries, _ := wavesFS.ReadDir("waves")
+	for _, entry := range entries {
+		names = append(names, entry.Name()[:len(entry.Name())-len(".json")])
+	}
+	return names
+}
+
+// Built-in wave set by name, see `BuiltinWaveNames`.
+func BuiltinWaves(name string) (*WaveSet, error) {
+	f, err := wavesFS.Open("waves/" + name + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Errors.WavesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return LoadWaves(f)
+}
+
+// Read and validate a wave set, errors point at the offending round and group.
+func LoadWaves(r io.Reader) (*WaveSet, error) {
+	raw := struct {
+		Name    string              `json:"name"`
+		Rounds  [][]json.RawMessage `json:"rounds"`
+		Endless []json.RawMessage   `json:"endless"`
+		Boss    *BossWave           `json:"boss"`
+	}{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, errors.New("waves: " + err.Error())
+	}
+
+	waves := &WaveSet{Name: raw.Name, Rounds: [][]WaveGroup{}, Endless: []EndlessGroup{}, Boss: raw.Boss}
+	for i, round := range raw.Rounds {
+		groups := []WaveGroup{}
+		for j, entry := range round {
+			path := "rounds[" + strconv.Itoa(i) + "][" + strconv.Itoa(j) + "]"
+			group := WaveGroup{}
+			if err := decodeStrict(entry, &group); err != nil {
+				return nil, waves.error(path, err.Error())
+			}
+			if err := group.validate(); err != nil {
+				return nil, waves.error(path, err.Error())
+			}
+			groups = append(groups, group)
+		}
+		waves.Rounds = append(waves.Rounds, groups)
+	}
+
+	if len(raw.Endless) == 0 {
+		return nil, waves.error("endless", "no groups defined")
+	}
+	// Expressions are checked by evaluating them for the first endless round, later rounds clamp their results to valid groups.
+	rng := rand.New(rand.NewPCG(0, 0))
+	for i, entry := range raw.Endless {
+		path := "endless[" + strconv.Itoa(i) + "]"
+		expr := EndlessGroup{}
+		if err := decodeStrict(entry, &expr); err != nil {
+			return nil, waves.error(path, err.Error())
+		}
+		group, err := expr.eval(len(waves.Rounds)+1, rng)
+		if err == nil {
+			err = group.validate()
+		}
+		if err != nil {
+			return nil, waves.error(path, err.Error())
+		}
+		waves.Endless = append(waves.Endless, expr)
+	}
+
+	if waves.Boss != nil {
+		if err := waves.Boss.validate(); err != nil {
+			return nil, waves.error("boss", err.Error())
+		}
+		group, err := waves.Boss.group(waves.Boss.Every, rng)
+		if err == nil {
+			err = group.validate()
+		}
+		if err != nil {
+			return nil, waves.error("boss", err.Error())
+		}
+	}
+
+	return waves, nil
+}
+
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func (waves *WaveSet) error(path, msg string) error {
+	return errors.New("waves " + strconv.Quote(waves.Name) + ": " + path + ": " + msg)
+}
+
+// Groups spawned in round r, starting at round 1.
+func (waves *WaveSet) groups(r int, rng *rand.Rand) ([]WaveGroup, error) {
+	groups := []WaveGroup{}
+	if r <= len(waves.Rounds) {
+		groups = append(groups, waves.Rounds[r-1]...)
+	} else {
+		for i, expr := range waves.Endless {
+			group, err := expr.eval(r, rng)
+			if err != nil {
+				return nil, waves.error("endless["+strconv.Itoa(i)+"] round "+strconv.Itoa(r), err.Error())
+			}
+			groups = append(groups, group.clamp())
+		}
+	}
+
+	if waves.Boss != nil && r%waves.Boss.Every == 0 {
+		group, err := waves.Boss.group(r, rng)
+		if err != nil {
+			return nil, waves.error("boss round "+strconv.Itoa(r), err.Error())
+		}
+		groups = append(groups, group.clamp())
+	}
+	return groups, nil
+}
+
+func (group WaveGroup) validate() error {
+	switch {
+	case !slices.ContainsFunc(EnemyTypes, func(obj EnemyType) bool { return obj.Name == group.Type }):
+		return errors.New("type " + strconv.Quote(group.Type) + " is unknown")
+	case group.Count < 0:
+		return errors.New("count is negative")
+	case group.Count > maxGroupCount:
+		return errors.New("count is more than " + strconv.Itoa(maxGroupCount))
+	case group.Spacing < 0:
+		return errors.New("spacing is negative")
+	case group.Health < 1:
+		return errors.New("health is less than 1")
+	case group.Speed <= 0:
+		return errors.New("speed is not positive")
+	case group.Reward < 0:
+		return errors.New("reward is negative")
+	case group.Delay < 0:
+		return errors.New("delay is negative")
+	}
+	return nil
+}
+
+// Group moved into the ranges checked by `validate`.
+func (group WaveGroup) clamp() WaveGroup {
+	group.Count = min(max(group.Count, 0), maxGroupCount)
+	group.Spacing = max(group.Spacing, 0)
+	group.Health = max(group.Health, 1)
+	group.Speed = max(group.Speed, minGroupSpeed)
+	group.Reward = max(group.Reward, 0)
+	group.Delay = max(group.Delay, 0)
+	return group
+}
+
+// Evaluated in field order, expressions calling `rand` advance the game RNG, only fails on invalid expressions.
+func (expr EndlessGroup) eval(r int, rng *rand.Rand) (WaveGroup, error) {
+	group := WaveGroup{Type: expr.Type}
+	for _, field := range []struct {
+		src  string
+		dst  *int
+		dstF *float64
+	}{
+		{expr.Count, &group.Count, nil},
+		{expr.Spacing, &group.Spacing, nil},
+		{expr.Health, &group.Health, nil},
+		{expr.Speed, nil, &group.Speed},
+		{expr.Reward, &group.Reward, nil},
+		{expr.Delay, &group.Delay, nil},
+	} {
+		if field.src == "" {
+			continue
+		}
+		v, err := evalExpr(field.src, r, rng)
+		if err != nil {
+			return WaveGroup{}, err
+		}
+		if field.dstF != nil {
+			*field.dstF = v
+		} else {
+			*field.dst = int(v)
+		}
+	}
+	return group, nil
+}
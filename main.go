@@ -1,9 +1,11 @@
 package main
 
 import (
+	clheadless "ATowerDefense/client/headless"
 	clsdl "ATowerDefense/client/sdl"
 	cltui "ATowerDefense/client/tui"
 	"ATowerDefense/game"
+	"ATowerDefense/server"
 	"embed"
 	"fmt"
 	"os"
@@ -18,7 +20,24 @@ var (
 		FieldWidth       int     `switch:"w,-field-width"       default:"35"  help:"Game setting: Field Width"`
 		FieldHeight      int     `switch:"h,-field-height"      default:"20"  help:"Game setting: Field Height"`
 		RefundMultiplier float64 `switch:"r,-refund-multiplier" default:"0.8" help:"Game setting: Refund Multiplier"`
+		Seed             int     `switch:"s,-seed"              default:"0"   help:"Game setting: Seed, random when 0"`
+		Spawns           int     `switch:"S,-spawns"            default:"1"   help:"Game setting: Spawn points"`
+		Forks            int     `switch:"f,-forks"             default:"1"   help:"Game setting: Forks off the main road"`
+		Mode             string  `switch:"M,-mode"                            help:"Game setting: Game mode, roads, maze or versus, roads when empty"`
+		Economy          string  `switch:"E,-economy"                         help:"Game setting: Coins with several players, owner, split or shared, owner when empty"`
+		Map              string  `switch:"l,-map"                             help:"Game setting: Map file, overrides the field size, generated when empty"`
+		Edit             bool    `switch:"e,-edit"                            help:"Edit the map file of --map, ATowerDefense.map when empty"`
 		TUI              bool    `switch:"t,-tui"                             help:"Use TUI renderer"`
+		Towers           string  `switch:"T,-towers"                          help:"Game setting: Tower definitions file, built-in when empty"`
+		Waves            string  `switch:"W,-waves"                           help:"Game setting: Wave set, built-in name or file, default when empty"`
+		Replay           string  `switch:"p,-replay"                          help:"Play back a replay file"`
+		Headless         bool    `switch:"H,-headless"                        help:"Simulate without renderer and print the results"`
+		BuildOrder       string  `switch:"b,-build-order"                     help:"Headless: Build order file, lines of <round> <tower> [x y]"`
+		MaxRounds        int     `switch:"m,-max-rounds"        default:"100" help:"Headless: Stop after this round"`
+		Host             string  `switch:"n,-host"                            help:"Host games for other players on this address, like :7777"`
+		Connect          string  `switch:"c,-connect"                         help:"Join a game hosted on this address, like localhost:7777"`
+		Join             int     `switch:"j,-join"                            help:"Connect: Code of the game to join, pick or create one when 0"`
+		Spectate         bool    `switch:"v,-spectate"                        help:"Connect: Watch the game of --join without playing"`
 	}{})
 
 	//go:embed assets/*/*.png
@@ -32,6 +51,86 @@ func main() {
 		GameSpeed:        1,
 		RefundMultiplier: args.RefundMultiplier,
 		TickDelay:        time.Millisecond * 50,
+		Seed:             uint64(args.Seed),
+		Spawns:           args.Spawns,
+		Forks:            args.Forks,
+		Mode:             args.Mode,
+		Economy:          args.Economy,
+	}
+
+	if args.Connect != "" {
+		if err := connect(args.Connect); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Edit {
+		file := args.Map
+		if file == "" {
+			file = "ATowerDefense.map"
+		}
+		if err := clsdl.Edit(file, args.FieldWidth, args.FieldHeight, assets); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Towers != "" {
+		towers, err := loadTowers(args.Towers)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		gc.Towers = towers
+	}
+	if args.Waves != "" {
+		waves, err := loadWaves(args.Waves)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		gc.Waves = waves
+	}
+	if args.Map != "" {
+		m, err := loadMap(args.Map)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		gc.Map = m
+	}
+
+	if args.Host != "" {
+		if err := server.Run(gc, args.Host); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Replay != "" {
+		if err := replay(args.Replay); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Local games have a single player.
+	if gc.Mode == "versus" {
+		fmt.Println("versus needs several players, play it with --host and --connect")
+		os.Exit(1)
+	}
+
+	if args.Headless {
+		if err := clheadless.Run(gc, args.BuildOrder, args.MaxRounds); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	if args.TUI {
@@ -46,3 +145,71 @@ func main() {
 		}
 	}
 }
+
+func loadTowers(file string) ([]game.TowerType, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return game.LoadTowers(f)
+}
+
+func loadWaves(name string) (*game.WaveSet, error) {
+	if waves, err := game.BuiltinWaves(name); err != game.Errors.WavesNotExists {
+		return waves, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return game.LoadWaves(f)
+}
+
+func loadMap(file string) (*game.Map, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	return game.LoadMap(f)
+}
+
+func replay(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	gm, err := game.LoadReplay(f)
+	if err != nil {
+		return err
+	}
+	if args.TUI {
+		return cltui.Replay(gm)
+	}
+	return clsdl.Replay(gm, assets)
+}
+
+func connect(addr string) error {
+	// Settings of a created game, the TUI lets players change them first.
+	lc := server.LobbyConfig{
+		FieldWidth:       args.FieldWidth,
+		FieldHeight:      args.FieldHeight,
+		RefundMultiplier: args.RefundMultiplier,
+		Seed:             uint64(args.Seed),
+		Waves:            args.Waves,
+		Economy:          args.Economy,
+		Mode:             args.Mode,
+	}
+	if args.TUI {
+		return cltui.Connect(addr, args.Join, lc, args.Spectate)
+	}
+	return clsdl.Connect(addr, args.Join, lc, args.Spectate, assets)
+}
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"ATowerDefense/game"
+	"bytes"
+	"encoding/json"
+	"net"
+	"sync"
+)
+
+type (
+	// Player connected to a server, joins a game in the lobby and plays it once started.
+	Client struct {
+		// Mirror of the joined game once started, commands are sent to the host and the state is replaced by its snapshots.
+		Game *game.Game
+		// Player index in the joined game, -1 when spectating.
+		PID int
+
+		c   net.Conn
+		enc *json.Encoder
+		// Serializes requests, every request waits for its result.
+		mu      sync.Mutex
+		results chan Message
+
+		// Guards lobby and chat.
+		lobbyMu sync.Mutex
+		lobby   LobbyInfo
+		chat    []ChatMessage
+		updates chan struct{}
+		started chan struct{}
+
+		// Reason the connection closed, nil while connected or when closed by `Client.Close`.
+		err     error
+		closed  bool
+		closeMu sync.Mutex
+		done    chan struct{}
+	}
+	ChatMessage struct {
+		PID  int
+		Name string
+		Text string
+	}
+)
+
+// Connect to the server on addr, join a game with `Client.Create` or `Client.Join`.
+func Connect(addr string) (*Client, error) {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	cl := &Client{
+		c: c, enc: json.NewEncoder(c), results: make(chan Message),
+		updates: make(chan struct{}, 1), started: make(chan struct{}), done: make(chan struct{}),
+	}
+	go cl.receive(json.NewDecoder(c))
+	return cl, nil
+}
+
+func (cl *Client) receive(dec *json.Decoder) {
+	defer close(cl.done)
+	defer close(cl.results)
+	for {
+		msg := Message{}
+		if err := dec.Decode(&msg); err != nil {
+			cl.fail(err)
+			// Renderers exit once the game stops.
+			if cl.Game != nil {
+				_ = cl.Game.Stop()
+			}
+			return
+		}
+
+		switch msg.Kind {
+		case "state":
+			if err := cl.sync(msg.State); err != nil {
+				cl.fail(err)
+				_ = cl.c.Close()
+			}
+		case "lobby":
+			if msg.Lobby == nil {
+				continue
+			}
+			cl.lobbyMu.Lock()
+			cl.lobby = *msg.Lobby
+			cl.lobbyMu.Unlock()
+			cl.update()
+		case "chat":
+			cl.lobbyMu.Lock()
+			cl.chat = append(cl.chat, ChatMessage{PID: msg.PID, Name: playerName(msg.PID), Text: msg.Text})
+			cl.lobbyMu.Unlock()
+			cl.update()
+		case "result":
+			cl.results <- msg
+		}
+	}
+}
+
+// Replace the state of the mirror with a snapshot, creating the mirror on the first snapshot.
+func (cl *Client) sync(state []byte) error {
+	if cl.Game != nil {
+		return cl.Game.Sync(bytes.NewReader(state))
+	}
+	gm, err := game.Load(bytes.NewReader(state))
+	if err != nil {
+		return err
+	}
+	gm.SetRemote(cl.command)
+	cl.Game = gm
+	close(cl.started)
+	return nil
+}
+
+func (cl *Client) update() {
+	select {
+	case cl.updates <- struct{}{}:
+	default:
+	}
+}
+
+func (cl *Client) request(msg Message) (Message, error) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if err := cl.enc.Encode(msg); err != nil {
+		return Message{}, Errors.Closed
+	}
+	result, ok := <-cl.results
+	if !ok {
+		return result, Errors.Closed
+	} else if result.Error != "" {
+		return result, decodeError(result.Error)
+	}
+	return result, nil
+}
+
+func (cl *Client) command(cmd game.Command) error {
+	_, err := cl.request(Message{Kind: "command", Command: &cmd})
+	return err
+}
+
+// Games on the server that can be joined.
+func (cl *Client) List() ([]LobbyInfo, error) {
+	result, err := cl.request(Message{Kind: "list"})
+	return result.Games, err
+}
+
+// Create a game and join it as host.
+func (cl *Client) Create(cfg LobbyConfig) error {
+	result, err := cl.request(Message{Kind: "create", Config: &cfg})
+	if err != nil {
+		return err
+	}
+	cl.PID = result.PID
+	return nil
+}
+
+// Join the game with code, games that already started are played right away.
+func (cl *Client) Join(code int) error {
+	result, err := cl.request(Message{Kind: "join", Code: code})
+	if err != nil {
+		return err
+	}
+	cl.PID = result.PID
+	return nil
+}
+
+// Watch the game with code without playing, the mirrored game is only updated by the host.
+func (cl *Client) Spectate(code int) error {
+	result, err := cl.request(Message{Kind: "spectate", Code: code})
+	if err != nil {
+		return err
+	}
+	cl.PID = result.PID
+	return nil
+}
+
+func (cl *Client) SetReady(ready bool) error {
+	_, err := cl.request(Message{Kind: "ready", Ready: ready})
+	return err
+}
+
+func (cl *Client) Chat(text string) error {
+	_, err := cl.request(Message{Kind: "chat", Text: text})
+	return err
+}
+
+// Start the joined game, only allowed for the host once every other player is ready.
+func (cl *Client) Start() error {
+	_, err := cl.request(Message{Kind: "start"})
+	return err
+}
+
+// Lobby of the joined game and the chat messages received so far.
+func (cl *Client) Lobby() (LobbyInfo, []ChatMessage) {
+	cl.lobbyMu.Lock()
+	defer cl.lobbyMu.Unlock()
+	return cl.lobby, append([]ChatMessage{}, cl.chat...)
+}
+
+// Receives when the lobby changed or a chat message arrived.
+func (cl *Client) Updates() <-chan struct{} {
+	return cl.updates
+}
+
+// Closed once the joined game started and `Client.Game` is set.
+func (cl *Client) Started() <-chan struct{} {
+	return cl.started
+}
+
+// Closed once the connection closed.
+func (cl *Client) Done() <-chan struct{} {
+	return cl.done
+}
+
+func (cl *Client) fail(err error) {
+	cl.closeMu.Lock()
+	defer cl.closeMu.Unlock()
+	if !cl.closed && cl.err == nil {
+		cl.err = err
+	}
+}
+
+// Leave the game.
+func (cl *Client) Close() error {
+	cl.closeMu.Lock()
+	cl.closed = true
+	cl.closeMu.Unlock()
+
+	err := cl.c.Close()
+	<-cl.done
+	return err
+}
+
+// Reason the connection closed, nil while connected or when closed by `Client.Close`.
+func (cl *Client) Err() error {
+	<-cl.done
+	return cl.err
+}
@@ -0,0 +1,313 @@
+package server
+
+import (
+	"ATowerDefense/game"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type (
+	// Settings chosen by the player creating a game.
+	LobbyConfig struct {
+		// Field size, ignored when the host plays on a map.
+		FieldWidth       int
+		FieldHeight      int
+		RefundMultiplier float64
+		// Seed for the game RNG, random when 0.
+		Seed uint64
+		// Name of a built-in wave set, see `game.BuiltinWaveNames`, the wave set of the host when not set.
+		Waves string
+		// Valid economies: see `game.Economies`, the economy of the host when not set.
+		Economy string
+		// Valid modes: see `game.Modes`, the mode of the host when not set.
+		Mode string
+	}
+	LobbyInfo struct {
+		// Code to join the game with.
+		Code   int
+		Config LobbyConfig
+		// Player index of the host, the only player allowed to start the game.
+		Host    int
+		Players []LobbyPlayer
+		// Amount of connections watching the game.
+		Spectators int
+		Started    bool
+	}
+	LobbyPlayer struct {
+		PID   int
+		Name  string
+		Ready bool
+	}
+
+	lobby struct {
+		code   int
+		config LobbyConfig
+		gm     *game.Game
+		host   int
+		// Joined connections and whether they are ready.
+		members    map[*conn]bool
+		spectators map[*conn]bool
+		started    bool
+	}
+)
+
+const (
+	// Field sizes allowed for created games.
+	MinFieldSize, MaxFieldSize = 10, 999
+	// Codes of games are in [`minCode`, `maxCode`).
+	minCode, maxCode = 1000, 10000
+)
+
+func (lc LobbyConfig) String() string {
+	waves, economy, mode, seed := lc.Waves, lc.Economy, lc.Mode, strconv.FormatUint(lc.Seed, 10)
+	if waves == "" {
+		waves = "host"
+	}
+	if economy == "" {
+		economy = "host"
+	}
+	if mode == "" {
+		mode = "host"
+	}
+	if lc.Seed == 0 {
+		seed = "random"
+	}
+	return strconv.Itoa(lc.FieldWidth) + "x" + strconv.Itoa(lc.FieldHeight) +
+		", refund " + strconv.Itoa(int(lc.RefundMultiplier*100)) + "%" +
+		", waves " + waves + ", economy " + economy + ", mode " + mode + ", seed " + seed
+}
+
+func (lb *lobby) info() LobbyInfo {
+	info := LobbyInfo{Code: lb.code, Config: lb.config, Host: lb.host, Players: []LobbyPlayer{}, Spectators: len(lb.spectators), Started: lb.started}
+	for cn, ready := range lb.members {
+		info.Players = append(info.Players, LobbyPlayer{PID: cn.pid, Name: playerName(cn.pid), Ready: ready})
+	}
+	slices.SortFunc(info.Players, func(a, b LobbyPlayer) int { return a.PID - b.PID })
+	return info
+}
+
+func (srv *Server) games() []LobbyInfo {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	games := []LobbyInfo{}
+	for _, lb := range srv.lobbies {
+		games = append(games, lb.info())
+	}
+	slices.SortFunc(games, func(a, b LobbyInfo) int { return a.Code - b.Code })
+	return games
+}
+
+func (srv *Server) create(cn *conn, cfg LobbyConfig, result *Message) error {
+	if cfg.FieldWidth < MinFieldSize || cfg.FieldWidth > MaxFieldSize ||
+		cfg.FieldHeight < MinFieldSize || cfg.FieldHeight > MaxFieldSize ||
+		cfg.RefundMultiplier < 0 || cfg.RefundMultiplier > 1 ||
+		(cfg.Economy != "" && !slices.Contains(game.Economies, cfg.Economy)) ||
+		(cfg.Mode != "" && !slices.Contains(game.Modes, cfg.Mode)) {
+		return Errors.InvalidConfig
+	}
+	gc := srv.gc
+	gc.FieldWidth, gc.FieldHeight = cfg.FieldWidth, cfg.FieldHeight
+	gc.RefundMultiplier, gc.Seed = cfg.RefundMultiplier, cfg.Seed
+	if cfg.Economy != "" {
+		gc.Economy = cfg.Economy
+	}
+	if cfg.Mode != "" {
+		gc.Mode = cfg.Mode
+	}
+	// Lanes are generated in versus, maps are not divided into lanes.
+	if gc.Mode == "versus" {
+		gc.Map = nil
+	}
+	if cfg.Waves != "" {
+		waves, err := game.BuiltinWaves(cfg.Waves)
+		if err != nil {
+			return err
+		}
+		gc.Waves = waves
+	}
+
+	srv.mu.Lock()
+	if cn.lobby != nil {
+		srv.mu.Unlock()
+		return Errors.AlreadyJoined
+	}
+	code := minCode + rand.IntN(maxCode-minCode)
+	for srv.lobbies[code] != nil {
+		code = minCode + rand.IntN(maxCode-minCode)
+	}
+	lb := &lobby{code: code, config: cfg, gm: game.NewGame(gc), members: map[*conn]bool{}, spectators: map[*conn]bool{}}
+	srv.lobbies[code] = lb
+	srv.enter(cn, lb, result)
+	lb.host = cn.pid
+	srv.mu.Unlock()
+
+	srv.broadcast(lb, Message{Kind: "lobby", Lobby: srv.lobbyInfo(lb)})
+	return nil
+}
+
+func (srv *Server) join(cn *conn, code int, result *Message) error {
+	srv.mu.Lock()
+	lb := srv.lobbies[code]
+	if cn.lobby != nil {
+		srv.mu.Unlock()
+		return Errors.AlreadyJoined
+	} else if lb == nil {
+		srv.mu.Unlock()
+		return Errors.GameNotExists
+	}
+	srv.enter(cn, lb, result)
+	started := lb.started
+	srv.mu.Unlock()
+
+	srv.broadcast(lb, Message{Kind: "lobby", Lobby: srv.lobbyInfo(lb)})
+	if started {
+		srv.broadcastState(lb)
+	}
+	return nil
+}
+
+func (srv *Server) spectate(cn *conn, code int, result *Message) error {
+	srv.mu.Lock()
+	lb := srv.lobbies[code]
+	if cn.lobby != nil {
+		srv.mu.Unlock()
+		return Errors.AlreadyJoined
+	} else if lb == nil {
+		srv.mu.Unlock()
+		return Errors.GameNotExists
+	}
+	cn.lobby, cn.pid, cn.spectating = lb, -1, true
+	lb.spectators[cn] = true
+	result.Code, result.PID = lb.code, cn.pid
+	started := lb.started
+	srv.mu.Unlock()
+
+	srv.broadcast(lb, Message{Kind: "lobby", Lobby: srv.lobbyInfo(lb)})
+	if started {
+		srv.broadcastState(lb)
+	}
+	return nil
+}
+
+// Add cn to lb as new player, srv.mu must be held.
+func (srv *Server) enter(cn *conn, lb *lobby, result *Message) {
+	cn.lobby, cn.pid = lb, lb.gm.AddPlayer()
+	lb.members[cn] = false
+	result.Code, result.PID = lb.code, cn.pid
+}
+
+// Remove cn from its lobby, passing on the host or stopping the game when cn was the last player.
+func (srv *Server) leave(cn *conn) {
+	srv.mu.Lock()
+	lb := cn.lobby
+	if lb == nil {
+		srv.mu.Unlock()
+		return
+	}
+	delete(lb.members, cn)
+	delete(lb.spectators, cn)
+	cn.lobby = nil
+
+	if len(lb.members) == 0 {
+		delete(srv.lobbies, lb.code)
+		started := lb.started
+		// Spectators have nothing left to watch.
+		for spectator := range lb.spectators {
+			spectator.lobby = nil
+			_ = spectator.c.Close()
+		}
+		srv.mu.Unlock()
+		if started {
+			_ = lb.gm.Stop()
+		}
+		return
+	}
+	if lb.host == cn.pid && !cn.spectating {
+		lb.host = -1
+		for member := range lb.members {
+			if lb.host < 0 || member.pid < lb.host {
+				lb.host = member.pid
+			}
+		}
+	}
+	srv.mu.Unlock()
+
+	srv.broadcast(lb, Message{Kind: "lobby", Lobby: srv.lobbyInfo(lb)})
+}
+
+func (srv *Server) setReady(cn *conn, ready bool) error {
+	srv.mu.Lock()
+	lb := cn.lobby
+	if lb == nil {
+		srv.mu.Unlock()
+		return Errors.NotJoined
+	} else if cn.spectating {
+		srv.mu.Unlock()
+		return Errors.Spectating
+	}
+	lb.members[cn] = ready
+	srv.mu.Unlock()
+
+	srv.broadcast(lb, Message{Kind: "lobby", Lobby: srv.lobbyInfo(lb)})
+	return nil
+}
+
+func (srv *Server) chat(cn *conn, text string) error {
+	srv.mu.Lock()
+	lb, pid, spectating := cn.lobby, cn.pid, cn.spectating
+	srv.mu.Unlock()
+	if lb == nil {
+		return Errors.NotJoined
+	} else if spectating {
+		return Errors.Spectating
+	}
+
+	srv.broadcast(lb, Message{Kind: "chat", PID: pid, Text: text})
+	return nil
+}
+
+func (srv *Server) start(cn *conn) error {
+	srv.mu.Lock()
+	lb := cn.lobby
+	switch {
+	case lb == nil:
+		srv.mu.Unlock()
+		return Errors.NotJoined
+	case cn.spectating:
+		srv.mu.Unlock()
+		return Errors.Spectating
+	case lb.host != cn.pid:
+		srv.mu.Unlock()
+		return Errors.NotHost
+	case lb.started:
+		srv.mu.Unlock()
+		return Errors.AlreadyStarted
+	}
+	for member, ready := range lb.members {
+		if member != cn && !ready {
+			srv.mu.Unlock()
+			return Errors.NotReady
+		}
+	}
+	if err := lb.gm.Start(); err != nil {
+		srv.mu.Unlock()
+		return err
+	}
+	lb.started = true
+	srv.mu.Unlock()
+
+	go func() { _ = lb.gm.Run(func(time.Duration) error { return nil }) }()
+	srv.broadcast(lb, Message{Kind: "lobby", Lobby: srv.lobbyInfo(lb)})
+	srv.broadcastState(lb)
+	return nil
+}
+
+func (srv *Server) lobbyInfo(lb *lobby) *LobbyInfo {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	info := lb.info()
+	return &info
+}
@@ -0,0 +1,347 @@
+// Host games over TCP for players connecting with `Connect`.
+//
+// Both sides send JSON encoded `Message`s, one per line.
+// Every message sent by a player is answered with a `result`, holding the error when the request failed.
+//
+//   - `list`: The result holds the `Games` that can be joined.
+//   - `create`: Create a game with `Config` and join it as host, the result holds the `Code` and `PID` of the player.
+//   - `join`: Join the game with `Code`, the result holds the `PID` of the player.
+//   - `spectate`: Watch the game with `Code` without playing, spectators receive every message but can not send any besides `list`.
+//   - `ready`: Set whether the player is `Ready` to start.
+//   - `chat`: Send `Text` to every player of the game.
+//   - `start`: Start the game, only allowed for the host once every other player is ready.
+//   - `command`: Apply `Command` to the game once started, the player index is replaced by the one of the player.
+//
+// The server sends on its own:
+//
+//   - `lobby`: The `Lobby` of the joined game, whenever a player joins, leaves or changes.
+//   - `chat`: `Text` sent by the player `PID`.
+//   - `state`: A snapshot of the started game in `State`, after every applied command and every `SnapshotInterval`.
+//
+// Hosts leaving pass the host on to the next player, games are stopped and spectators disconnected once every player left.
+package server
+
+import (
+	"ATowerDefense/game"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	serverErrors struct {
+		NotJoined, AlreadyJoined, GameNotExists,
+		NotHost, NotReady, NotStarted, AlreadyStarted,
+		Spectating, InvalidConfig, UnexpectedMessage, Closed error
+	}
+
+	Message struct {
+		// Valid kinds: `list`, `create`, `join`, `spectate`, `ready`, `chat`, `start`, `command`, `result`, `lobby`, `state`
+		Kind string
+		// Player index of the joined player on results, -1 for spectators, of the sender on chat.
+		PID int `json:",omitempty"`
+		// Code of the game to join, of the joined game on results.
+		Code int `json:",omitempty"`
+		// Settings of a created game.
+		Config *LobbyConfig `json:",omitempty"`
+		// Games that can be joined.
+		Games []LobbyInfo `json:",omitempty"`
+		// Lobby of the joined game.
+		Lobby *LobbyInfo `json:",omitempty"`
+		// Whether the player is ready to start.
+		Ready bool `json:",omitempty"`
+		// Chat message.
+		Text string `json:",omitempty"`
+		// Command to apply to the started game.
+		Command *game.Command `json:",omitempty"`
+		// Snapshot of the game written by `game.Game.Save`.
+		State json.RawMessage `json:",omitempty"`
+		// Error of a failed request.
+		Error string `json:",omitempty"`
+	}
+
+	Server struct {
+		// Settings of created games not set by `LobbyConfig`.
+		gc game.GameConfig
+		ln net.Listener
+
+		// Guards everything below and the lobbies and connections within.
+		mu      sync.Mutex
+		lobbies map[int]*lobby
+		conns   map[*conn]bool
+	}
+	conn struct {
+		c net.Conn
+		// Joined lobby, nil when not joined.
+		lobby *lobby
+		pid   int
+		// Watching lobby without playing, pid is -1.
+		spectating bool
+		// Serializes writes of the connection handler and broadcasts.
+		writeMu sync.Mutex
+	}
+)
+
+const (
+	// Time between snapshots sent to every player while no commands are applied.
+	SnapshotInterval = time.Millisecond * 100
+	// Connections taking longer to accept a message are closed.
+	writeTimeout = time.Second * 5
+)
+
+var Errors = serverErrors{
+	NotJoined:         errors.New("player has not joined a game"),
+	AlreadyJoined:     errors.New("player already joined a game"),
+	GameNotExists:     errors.New("game does not exists"),
+	NotHost:           errors.New("player is not the host"),
+	NotReady:          errors.New("players are not ready"),
+	NotStarted:        errors.New("game has not started"),
+	AlreadyStarted:    errors.New("game already started"),
+	Spectating:        errors.New("player is spectating"),
+	InvalidConfig:     errors.New("game settings are invalid"),
+	UnexpectedMessage: errors.New("message is unexpected"),
+	Closed:            errors.New("connection is closed"),
+}
+
+// Host games on addr until the process is stopped, gc holds the settings not chosen by players.
+func Run(gc game.GameConfig, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Hosting on " + ln.Addr().String())
+	return New(gc).Serve(ln)
+}
+
+func New(gc game.GameConfig) *Server {
+	return &Server{gc: gc, lobbies: map[int]*lobby{}, conns: map[*conn]bool{}}
+}
+
+// Accept players on ln until `Server.Close` is called.
+func (srv *Server) Serve(ln net.Listener) error {
+	srv.mu.Lock()
+	srv.ln = ln
+	srv.mu.Unlock()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(SnapshotInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				srv.mu.Lock()
+				lobbies := []*lobby{}
+				for _, lb := range srv.lobbies {
+					if lb.started {
+						lobbies = append(lobbies, lb)
+					}
+				}
+				srv.mu.Unlock()
+				for _, lb := range lobbies {
+					srv.broadcastState(lb)
+				}
+			}
+		}
+	}()
+
+	for {
+		c, err := ln.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		go srv.handle(c)
+	}
+}
+
+// Stop accepting players, disconnect every player and stop every game.
+func (srv *Server) Close() error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	for cn := range srv.conns {
+		_ = cn.c.Close()
+	}
+	for _, lb := range srv.lobbies {
+		if lb.started {
+			_ = lb.gm.Stop()
+		}
+	}
+	if srv.ln == nil {
+		return nil
+	}
+	return srv.ln.Close()
+}
+
+func (srv *Server) handle(c net.Conn) {
+	cn := &conn{c: c}
+	srv.mu.Lock()
+	srv.conns[cn] = true
+	srv.mu.Unlock()
+	defer func() {
+		srv.leave(cn)
+		srv.mu.Lock()
+		delete(srv.conns, cn)
+		srv.mu.Unlock()
+		_ = c.Close()
+	}()
+
+	dec := json.NewDecoder(c)
+	for {
+		msg := Message{}
+		if err := dec.Decode(&msg); err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Printf("%v: %v", c.RemoteAddr(), err)
+			}
+			return
+		}
+
+		result := Message{Kind: "result"}
+		if err := srv.request(cn, msg, &result); err != nil {
+			result.Error = err.Error()
+		}
+		if err := cn.send(result); err != nil {
+			return
+		}
+	}
+}
+
+func (srv *Server) request(cn *conn, msg Message, result *Message) error {
+	switch msg.Kind {
+	case "list":
+		result.Games = srv.games()
+		return nil
+	case "create":
+		if msg.Config == nil {
+			return Errors.InvalidConfig
+		}
+		return srv.create(cn, *msg.Config, result)
+	case "join":
+		return srv.join(cn, msg.Code, result)
+	case "spectate":
+		return srv.spectate(cn, msg.Code, result)
+	case "ready":
+		return srv.setReady(cn, msg.Ready)
+	case "chat":
+		return srv.chat(cn, msg.Text)
+	case "start":
+		return srv.start(cn)
+	case "command":
+		if msg.Command == nil {
+			return Errors.UnexpectedMessage
+		}
+		return srv.command(cn, *msg.Command)
+	}
+	return Errors.UnexpectedMessage
+}
+
+func (srv *Server) command(cn *conn, cmd game.Command) error {
+	srv.mu.Lock()
+	lb, pid, spectating := cn.lobby, cn.pid, cn.spectating
+	started := lb != nil && lb.started
+	srv.mu.Unlock()
+	if lb == nil {
+		return Errors.NotJoined
+	} else if spectating {
+		return Errors.Spectating
+	} else if !started {
+		return Errors.NotStarted
+	} else if cmd.Kind == "player" || cmd.Kind == "start" {
+		// Players are added by joining and games started by the host.
+		return Errors.UnexpectedMessage
+	}
+
+	cmd.PID = pid
+	if err := lb.gm.Apply(cmd); err != nil {
+		return err
+	}
+	// The new state arrives before the result, players see the effect of their command once it returns.
+	srv.broadcastState(lb)
+	return nil
+}
+
+// Send the state of a started game to all of its players and spectators.
+func (srv *Server) broadcastState(lb *lobby) {
+	buf := &bytes.Buffer{}
+	if err := lb.gm.Save(buf); err != nil {
+		log.Printf("game %v: %v", lb.code, err)
+		return
+	}
+	srv.broadcast(lb, Message{Kind: "state", State: buf.Bytes()})
+}
+
+func (srv *Server) broadcast(lb *lobby, msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("game %v: %v", lb.code, err)
+		return
+	}
+	data = append(data, '\n')
+
+	srv.mu.Lock()
+	conns := make([]*conn, 0, len(lb.members)+len(lb.spectators))
+	for cn := range lb.members {
+		conns = append(conns, cn)
+	}
+	for cn := range lb.spectators {
+		conns = append(conns, cn)
+	}
+	srv.mu.Unlock()
+
+	for _, cn := range conns {
+		if err := cn.write(data); errors.Is(err, net.ErrClosed) {
+			continue
+		} else if err != nil {
+			log.Printf("game %v: %v: %v", lb.code, cn.c.RemoteAddr(), err)
+			_ = cn.c.Close()
+		}
+	}
+}
+
+func (cn *conn) send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return cn.write(append(data, '\n'))
+}
+
+func (cn *conn) write(data []byte) error {
+	cn.writeMu.Lock()
+	defer cn.writeMu.Unlock()
+
+	if err := cn.c.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	_, err := cn.c.Write(data)
+	return err
+}
+
+func playerName(pid int) string {
+	return "P" + strconv.Itoa(pid+1)
+}
+
+// Error of the game or server matching msg, so errors of requests can be compared with `game.Errors` and `Errors`.
+func decodeError(msg string) error {
+	for _, errs := range []reflect.Value{reflect.ValueOf(game.Errors), reflect.ValueOf(Errors)} {
+		for i := range errs.NumField() {
+			if err, ok := errs.Field(i).Interface().(error); ok && err.Error() == msg {
+				return err
+			}
+		}
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"ATowerDefense/game"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Host a game on loopback for 2 players, both started and running their mirror.
+func startGame(t *testing.T) (*Client, *Client) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(game.GameConfig{GameSpeed: 1, TickDelay: time.Millisecond * 10})
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	a, err := Connect(ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = a.Close() })
+	b, err := Connect(ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = b.Close() })
+
+	if err := a.Create(LobbyConfig{FieldWidth: 20, FieldHeight: 12, RefundMultiplier: 0.5, Seed: 5}); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := a.Lobby()
+	if err := b.Join(info.Code); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetReady(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cl := range []*Client{a, b} {
+		select {
+		case <-cl.Started():
+		case <-time.After(time.Second * 5):
+			t.Fatal("game not started")
+		}
+		go func() { _ = cl.Game.Run(func(time.Duration) error { return nil }) }()
+		t.Cleanup(func() { _ = cl.Game.Stop() })
+	}
+	return a, b
+}
+
+// Place the first tower type on the first free tile of from, returns its position.
+func placeTower(t *testing.T, from *Client) (int, int) {
+	t.Helper()
+
+	for y := range 12 {
+		for x := range 20 {
+			err := from.Game.PlaceTower(game.Towers[0].Name, x, y, from.PID)
+			if err == nil {
+				return x, y
+			} else if err != game.Errors.InvalidPlacement && err != game.Errors.PathBlocked {
+				t.Fatal(err)
+			}
+		}
+	}
+	t.Fatal("no free tile")
+	return 0, 0
+}
+
+// Wait until the mirror of cl has a tower of owner on x, y.
+func waitTower(t *testing.T, cl *Client, x, y, owner int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second * 5)
+	for time.Now().Before(deadline) {
+		found := false
+		cl.Game.View(func() {
+			towers := cl.Game.GetCollisionTowers(x, y)
+			found = len(towers) == 1 && towers[0].Owner == owner
+		})
+		if found {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatalf("tower of P%v on %v, %v not mirrored to P%v", owner+1, x, y, cl.PID+1)
+}
+
+func TestCommandsMirrored(t *testing.T) {
+	a, b := startGame(t)
+
+	x, y := placeTower(t, a)
+	waitTower(t, b, x, y, a.PID)
+
+	x, y = placeTower(t, b)
+	waitTower(t, a, x, y, b.PID)
+}
+
+func TestCommandsRejected(t *testing.T) {
+	a, b := startGame(t)
+
+	x, y := placeTower(t, a)
+	waitTower(t, b, x, y, a.PID)
+	if err := b.Game.DestroyTower(x, y, b.PID); err != game.Errors.InvalidPlayer {
+		t.Fatalf("destroying the tower of another player: %v", err)
+	}
+	if err := b.Game.Restore(strings.NewReader("{}")); err != game.Errors.RemoteGame {
+		t.Fatalf("restoring a mirror: %v", err)
+	}
+}